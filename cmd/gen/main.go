@@ -0,0 +1,58 @@
+// Command gen reads a GraphQL SDL schema file and prints the Go source
+// that builds the equivalent graphql-go SchemaConfig.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"go/format"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/shoobyban/graphql-schema/generator"
+)
+
+func main() {
+	mode := flag.String("mode", "map", `generation mode: "map" emits Resolve: resolves["field"] lookups; "typed" also emits a gqlgen-style resolver interface and Adapter per object type, and a Go struct per input type`)
+	flag.Usage = func() {
+		log.Printf("Usage: ./gen [-mode=map|typed] {foo.graphql}")
+	}
+	flag.Parse()
+	if *mode != "map" && *mode != "typed" {
+		log.Fatalf("unknown -mode %q, want \"map\" or \"typed\"", *mode)
+	}
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(-1)
+	}
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("Can't open file %s", flag.Arg(0))
+	}
+	defer f.Close()
+	byteValue, _ := ioutil.ReadAll(f)
+	src := source.NewSource(&source.Source{
+		Body: byteValue,
+		Name: "GraphQL Schema",
+	})
+	doc, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		log.Fatalf("failed to parse schema file, error: %v", err)
+	}
+	out, err := generator.NewGenerator().Generate(doc, *mode)
+	if err != nil {
+		log.Fatalf("%s: %v", flag.Arg(0), err)
+	}
+	b, err := format.Source([]byte(out))
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println(out)
+	} else {
+		fmt.Println(string(b))
+	}
+}
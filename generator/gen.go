@@ -0,0 +1,664 @@
+// Package generator turns a parsed GraphQL SDL document into Go source
+// that builds the equivalent graphql-go SchemaConfig.
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+)
+
+// GenError reports a problem found while generating code, with the
+// source position of the offending reference so the caller doesn't have
+// to go hunting through the schema file for it.
+type GenError struct {
+	Line, Column int
+	Msg          string
+}
+
+func (e *GenError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// Generator turns a parsed GraphQL SDL document into Go source that builds
+// the equivalent graphql-go SchemaConfig. All state lives on the struct,
+// so a Generator is only ever used for one document and is safe to reuse
+// as a library (unlike the old package-level var/goroutine approach).
+type Generator struct {
+	defs         map[string]ast.Node // named type definitions, keyed by SDL name
+	dependencies map[string][]string // SDL name -> names of types it references
+	directives   map[string]string   // directive name -> location used to register it
+
+	varBlocks map[string]string // SDL name -> generated "var xObject = ..." block
+	fields    []string          // generated AddFieldConfig calls, in definition order
+}
+
+// NewGenerator creates an empty Generator ready to walk a single document.
+func NewGenerator() *Generator {
+	return &Generator{
+		defs:         map[string]ast.Node{},
+		dependencies: map[string][]string{},
+		directives:   map[string]string{},
+		varBlocks:    map[string]string{},
+	}
+}
+
+// Generate walks doc and returns the Go source for a getSchema function, or
+// the first error encountered, identified by its position in the schema.
+// In "typed" mode it also emits, ahead of getSchema, a resolver interface
+// and FieldResolveFn Adapter for every object type and a Go struct for
+// every input type; see gen_typed.go.
+func (g *Generator) Generate(doc *ast.Document, mode string) (string, error) {
+	extraFields := map[string][]*ast.FieldDefinition{}
+	for _, child := range doc.Definitions {
+		if ext, ok := child.(*ast.TypeExtensionDefinition); ok {
+			extraFields[ext.Definition.Name.Value] = append(extraFields[ext.Definition.Name.Value], ext.Definition.Fields...)
+		}
+	}
+
+	for _, child := range doc.Definitions {
+		name := definitionName(child)
+		if name == "" {
+			continue
+		}
+		if obj, ok := child.(*ast.ObjectDefinition); ok {
+			obj.Fields = append(obj.Fields, extraFields[name]...)
+		}
+		g.defs[name] = child
+		g.dependencies[name] = dependenciesOf(child)
+	}
+
+	for _, name := range g.topoOrder() {
+		var err error
+		switch n := g.defs[name].(type) {
+		case *ast.ObjectDefinition:
+			err = g.processObject(n)
+		case *ast.EnumDefinition:
+			err = g.processEnum(n)
+		case *ast.InterfaceDefinition:
+			err = g.processInterface(n)
+		case *ast.UnionDefinition:
+			err = g.processUnion(n)
+		case *ast.InputObjectDefinition:
+			err = g.processInput(n)
+		case *ast.ScalarDefinition:
+			err = g.processScalar(n)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	schemaBlock := ""
+	for _, child := range doc.Definitions {
+		if n, ok := child.(*ast.SchemaDefinition); ok {
+			block, err := g.processSchema(n)
+			if err != nil {
+				return "", err
+			}
+			schemaBlock = block
+			break
+		}
+	}
+	if schemaBlock == "" {
+		schemaBlock = g.defaultSchema()
+	}
+
+	out := "package dummy\n"
+	if mode == "typed" {
+		out += "import (\n\t\"context\"\n\n\t\"github.com/graphql-go/graphql\"\n)\n"
+		typed, err := g.generateTyped()
+		if err != nil {
+			return "", err
+		}
+		out += typed
+	} else {
+		out += "import (\"github.com/graphql-go/graphql\")\n"
+	}
+	out += g.directiveDefs()
+	out += "func getSchema(resolves map[string]graphql.FieldResolveFn, scalars map[string]graphql.ScalarConfig) graphql.SchemaConfig {\n"
+	for _, name := range g.topoOrder() {
+		out += g.varBlocks[name]
+	}
+	for _, f := range g.fields {
+		out += f
+	}
+	out += schemaBlock
+	out += "\n return schema\n}"
+	return out, nil
+}
+
+// definitionName returns the SDL name of a top-level definition that can be
+// referenced by other definitions, or "" for definitions (like "schema {
+// ... }") that aren't named types.
+func definitionName(child ast.Node) string {
+	switch n := child.(type) {
+	case *ast.ObjectDefinition:
+		return n.Name.Value
+	case *ast.EnumDefinition:
+		return n.Name.Value
+	case *ast.InterfaceDefinition:
+		return n.Name.Value
+	case *ast.UnionDefinition:
+		return n.Name.Value
+	case *ast.InputObjectDefinition:
+		return n.Name.Value
+	case *ast.ScalarDefinition:
+		return n.Name.Value
+	}
+	return ""
+}
+
+// typeName unwraps List/NonNull wrappers down to the base named type.
+func typeName(t ast.Type) string {
+	switch n := t.(type) {
+	case *ast.NonNull:
+		return typeName(n.Type)
+	case *ast.List:
+		return typeName(n.Type)
+	case *ast.Named:
+		return n.Name.Value
+	}
+	return ""
+}
+
+// unwrapNonNull strips a leading NonNull wrapper, if any, so callers that
+// only care about the underlying List/Named shape don't have to special
+// case a "[Status!]!" the same way they already do a bare "[Status!]".
+func unwrapNonNull(t ast.Type) ast.Type {
+	if n, ok := t.(*ast.NonNull); ok {
+		return n.Type
+	}
+	return t
+}
+
+// dependenciesOf lists the named types a definition references, so the
+// generator can emit definitions in dependency order.
+func dependenciesOf(def ast.Node) []string {
+	var names []string
+	switch n := def.(type) {
+	case *ast.ObjectDefinition:
+		for _, f := range n.Fields {
+			names = append(names, typeName(f.Type))
+			for _, a := range f.Arguments {
+				names = append(names, typeName(a.Type))
+			}
+		}
+	case *ast.InterfaceDefinition:
+		for _, f := range n.Fields {
+			names = append(names, typeName(f.Type))
+			for _, a := range f.Arguments {
+				names = append(names, typeName(a.Type))
+			}
+		}
+	case *ast.UnionDefinition:
+		for _, t := range n.Types {
+			names = append(names, typeName(t))
+		}
+	case *ast.InputObjectDefinition:
+		for _, f := range n.Fields {
+			names = append(names, typeName(f.Type))
+		}
+	}
+	return names
+}
+
+// topoOrder returns every registered type name ordered so that each type
+// referenced by another comes before it. Reference cycles collapse into a
+// single strongly connected component (Tarjan's algorithm); within a
+// component, and when choosing where to start, names are ordered
+// alphabetically so two runs over the same schema always emit identical
+// source.
+func (g *Generator) topoOrder() []string {
+	names := make([]string, 0, len(g.defs))
+	for name := range g.defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	index := map[string]int{}
+	low := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	counter := 0
+	var order []string
+
+	var strongconnect func(v string)
+	strongconnect = func(v string) {
+		index[v] = counter
+		low[v] = counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		deps := append([]string{}, g.dependencies[v]...)
+		sort.Strings(deps)
+		for _, w := range deps {
+			if _, ok := g.defs[w]; !ok {
+				continue // scalar or other type we don't emit a var for
+			}
+			if _, seen := index[w]; !seen {
+				strongconnect(w)
+				if low[w] < low[v] {
+					low[v] = low[w]
+				}
+			} else if onStack[w] {
+				if index[w] < low[v] {
+					low[v] = index[w]
+				}
+			}
+		}
+
+		if low[v] == index[v] {
+			var scc []string
+			for {
+				n := len(stack) - 1
+				w := stack[n]
+				stack = stack[:n]
+				onStack[w] = false
+				scc = append(scc, w)
+				if w == v {
+					break
+				}
+			}
+			sort.Strings(scc)
+			order = append(order, scc...)
+		}
+	}
+
+	for _, name := range names {
+		if _, seen := index[name]; !seen {
+			strongconnect(name)
+		}
+	}
+	return order
+}
+
+// errorAt wraps a message with the source position of loc, falling back to
+// a plain error if the node carries no location (e.g. it was synthesized).
+func (g *Generator) errorAt(loc *ast.Location, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if loc == nil || loc.Source == nil {
+		return fmt.Errorf("%s", msg)
+	}
+	l := location.GetLocation(loc.Source, loc.Start)
+	return &GenError{Line: l.Line, Column: l.Column, Msg: msg}
+}
+
+// registerDirective records that a non-builtin directive was used somewhere
+// in the schema so it can be emitted once as a graphql.NewDirective at the
+// top of the generated file.
+func (g *Generator) registerDirective(name, loc string) {
+	switch name {
+	case "deprecated", "include", "skip":
+		return
+	}
+	if _, ok := g.directives[name]; !ok {
+		g.directives[name] = loc
+	}
+}
+
+// deprecationReason returns the "reason" argument of an @deprecated
+// directive, if present, registering every other directive it sees.
+func (g *Generator) deprecationReason(dirs []*ast.Directive, loc string) string {
+	reason := ""
+	for _, d := range dirs {
+		if d.Name.Value == "deprecated" {
+			for _, a := range d.Arguments {
+				if a.Name.Value == "reason" {
+					if s, ok := a.Value.(*ast.StringValue); ok {
+						reason = s.Value
+					}
+				}
+			}
+			continue
+		}
+		g.registerDirective(d.Name.Value, loc)
+	}
+	return reason
+}
+
+// descriptionField renders a Description: line for the given doc comment,
+// or the empty string if there is none. desc may be a triple-quoted block
+// comment or a single-line string, both of which the lexer hands back as a
+// plain *ast.StringValue.
+func descriptionField(desc *ast.StringValue) string {
+	if desc == nil || desc.Value == "" {
+		return ""
+	}
+	return fmt.Sprintf("Description: %q,\n", desc.Value)
+}
+
+// resolveType renders the graphql-go expression for an SDL type reference,
+// reporting an error at the reference's source position if it names a type
+// that was never declared.
+func (g *Generator) resolveType(t ast.Type) (string, error) {
+	switch n := t.(type) {
+	case *ast.NonNull:
+		inner, err := g.resolveType(n.Type)
+		if err != nil {
+			return "", err
+		}
+		return "graphql.NewNonNull(" + inner + ")", nil
+	case *ast.List:
+		inner, err := g.resolveType(n.Type)
+		if err != nil {
+			return "", err
+		}
+		return "graphql.NewList(" + inner + ")", nil
+	case *ast.Named:
+		return g.goVarName(n.Name.Value, n.Loc)
+	default:
+		return "", g.errorAt(nil, "unhandled type %T", t)
+	}
+}
+
+// goVarName returns the package-level Go identifier a declared SDL name
+// generates, or an error at loc if the name was never declared.
+func (g *Generator) goVarName(orig string, loc *ast.Location) (string, error) {
+	switch orig {
+	case "String", "ID", "Int", "Float", "Boolean":
+		return "graphql." + orig, nil
+	}
+	def, ok := g.defs[orig]
+	if !ok {
+		return "", g.errorAt(loc, "not a declared scalar, object, enum, interface, union or input type: %q", orig)
+	}
+	switch n := def.(type) {
+	case *ast.ObjectDefinition:
+		if n.Name.Value == "Query" {
+			return "rootQuery", nil
+		}
+		if n.Name.Value == "Mutation" {
+			return "rootMutation", nil
+		}
+		return strings.ToLower(orig) + "Object", nil
+	case *ast.EnumDefinition:
+		return strings.ToLower(orig) + "Type", nil
+	case *ast.InterfaceDefinition:
+		return strings.ToLower(orig) + "Interface", nil
+	case *ast.UnionDefinition:
+		return strings.ToLower(orig) + "Union", nil
+	case *ast.InputObjectDefinition:
+		return strings.ToLower(orig) + "Input", nil
+	case *ast.ScalarDefinition:
+		return strings.ToLower(orig) + "Scalar", nil
+	}
+	return "", g.errorAt(loc, "unexpected definition kind for %q", orig)
+}
+
+// goLiteral renders a Go literal expression for a default-value AST node.
+// It supports the literal shapes GraphQL allows in a default value
+// position: ints, floats, strings, booleans, enums and lists of those. t is
+// the declared type the default value is for, needed to resolve an
+// *ast.EnumValue to the int index processEnum assigned it.
+func (g *Generator) goLiteral(v ast.Value, t ast.Type, loc *ast.Location) (string, error) {
+	switch n := v.(type) {
+	case *ast.IntValue:
+		return n.Value, nil
+	case *ast.FloatValue:
+		return n.Value, nil
+	case *ast.StringValue:
+		return fmt.Sprintf("%q", n.Value), nil
+	case *ast.BooleanValue:
+		return strconv.FormatBool(n.Value), nil
+	case *ast.EnumValue:
+		return g.enumValueLiteral(n, t, loc)
+	case *ast.ListValue:
+		var elemType ast.Type
+		if list, ok := unwrapNonNull(t).(*ast.List); ok {
+			elemType = list.Type
+		}
+		parts := make([]string, 0, len(n.Values))
+		for _, e := range n.Values {
+			lit, err := g.goLiteral(e, elemType, loc)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, lit)
+		}
+		return "[]interface{}{" + strings.Join(parts, ", ") + "}", nil
+	}
+	return "", g.errorAt(loc, "unsupported default value literal %T", v)
+}
+
+// enumValueLiteral resolves an enum default value to the int index
+// processEnum assigned its name within t's declaration, the same Value
+// graphql-go stores for that member.
+func (g *Generator) enumValueLiteral(n *ast.EnumValue, t ast.Type, loc *ast.Location) (string, error) {
+	def, _ := g.defs[typeName(t)].(*ast.EnumDefinition)
+	if def == nil {
+		return "", g.errorAt(loc, "%q is not a declared enum value", n.Value)
+	}
+	for i, v := range def.Values {
+		if v.Name.Value == n.Value {
+			return strconv.Itoa(i), nil
+		}
+	}
+	return "", g.errorAt(loc, "%q is not a member of enum %q", n.Value, def.Name.Value)
+}
+
+// defaultValueField renders a DefaultValue: line for an argument or input
+// field, or "" if it has no default.
+func (g *Generator) defaultValueField(v ast.Value, t ast.Type, loc *ast.Location) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	lit, err := g.goLiteral(v, t, loc)
+	if err != nil {
+		return "", err
+	}
+	return "DefaultValue: " + lit + ",\n", nil
+}
+
+// processScalar emits a graphql.NewScalar whose Serialize/ParseValue/
+// ParseLiteral hooks are supplied by the caller of getSchema through the
+// scalars map, the same extension point resolves is for fields.
+func (g *Generator) processScalar(n *ast.ScalarDefinition) error {
+	varname := strings.ToLower(n.Name.Value) + "Scalar"
+	out := "var " + varname + " = graphql.NewScalar(graphql.ScalarConfig{\n\tName: \"" + n.Name.Value + "\",\n"
+	out += descriptionField(n.Description)
+	g.deprecationReason(n.Directives, graphql.DirectiveLocationScalar)
+	out += fmt.Sprintf("\tSerialize: scalars[%q].Serialize,\n", n.Name.Value)
+	out += fmt.Sprintf("\tParseValue: scalars[%q].ParseValue,\n", n.Name.Value)
+	out += fmt.Sprintf("\tParseLiteral: scalars[%q].ParseLiteral,\n", n.Name.Value)
+	g.varBlocks[n.Name.Value] = out + "})\n"
+	return nil
+}
+
+func (g *Generator) processInput(u *ast.InputObjectDefinition) error {
+	varname := strings.ToLower(u.Name.Value) + "Input"
+	out := "var " + varname + " = graphql.NewInputObject(graphql.InputObjectConfig{\n\tName: \"" + u.Name.Value + "\",\n"
+	out += descriptionField(u.Description)
+	g.deprecationReason(u.Directives, graphql.DirectiveLocationInputObject)
+	if len(u.Fields) > 0 {
+		out += "\tFields: graphql.InputObjectConfigFieldMap{\n"
+		for _, f := range u.Fields {
+			t, err := g.resolveType(f.Type)
+			if err != nil {
+				return err
+			}
+			dv, err := g.defaultValueField(f.DefaultValue, f.Type, f.Loc)
+			if err != nil {
+				return err
+			}
+			out += fmt.Sprintf("\t\t\"%s\": &graphql.InputObjectFieldConfig{\n", f.Name.Value)
+			out += "\tType: " + t + ",\n"
+			out += descriptionField(f.Description)
+			out += dv
+			out += "},\n"
+		}
+		out += "\t},\n"
+	}
+	g.varBlocks[u.Name.Value] = out + "})\n"
+	return nil
+}
+
+func (g *Generator) processUnion(u *ast.UnionDefinition) error {
+	varname := strings.ToLower(u.Name.Value) + "Union"
+	out := "var " + varname + " = graphql.NewUnion(graphql.UnionConfig{\n\tName: \"" + u.Name.Value + "\",\n"
+	out += descriptionField(u.Description)
+	out += "\tTypes: []*graphql.Object{\n"
+	g.deprecationReason(u.Directives, graphql.DirectiveLocationUnion)
+	for _, t := range u.Types {
+		rt, err := g.resolveType(t)
+		if err != nil {
+			return err
+		}
+		out += "\t\t" + rt + ",\n"
+	}
+	g.varBlocks[u.Name.Value] = out + "}})\n"
+	return nil
+}
+
+func (g *Generator) processInterface(in *ast.InterfaceDefinition) error {
+	varname := strings.ToLower(in.Name.Value) + "Interface"
+	out := "var " + varname + " = graphql.NewInterface(graphql.InterfaceConfig{\n\tName: \"" + in.Name.Value + "\",\n"
+	out += descriptionField(in.Description)
+	out += "\tFields: graphql.Fields{\n"
+	g.deprecationReason(in.Directives, graphql.DirectiveLocationInterface)
+	for _, f := range in.Fields {
+		if err := g.addField(varname, f, false); err != nil {
+			return err
+		}
+	}
+	g.varBlocks[in.Name.Value] = out + "}})\n"
+	return nil
+}
+
+func (g *Generator) processEnum(n *ast.EnumDefinition) error {
+	varname := strings.ToLower(n.Name.Value) + "Type"
+	out := "var " + varname + " = graphql.NewEnum(graphql.EnumConfig{\n\tName: \"" + n.Name.Value + "\",\n"
+	out += descriptionField(n.Description)
+	out += "\tValues: graphql.EnumValueConfigMap{\n"
+	g.deprecationReason(n.Directives, graphql.DirectiveLocationEnum)
+	for i, e := range n.Values {
+		out += "\t\"" + e.Name.Value + "\": &graphql.EnumValueConfig{\n\t\tValue: " + strconv.Itoa(i) + ",\n"
+		out += "\t\t" + descriptionField(e.Description)
+		if reason := g.deprecationReason(e.Directives, graphql.DirectiveLocationEnumValue); reason != "" {
+			out += "\t\tDeprecationReason: \"" + reason + "\",\n"
+		}
+		out += "\t},\n"
+	}
+	g.varBlocks[n.Name.Value] = out + "}})\n"
+	return nil
+}
+
+func (g *Generator) processSchema(n *ast.SchemaDefinition) (string, error) {
+	g.deprecationReason(n.Directives, graphql.DirectiveLocationSchema)
+	out := "var schema = graphql.SchemaConfig{\n"
+	for _, ot := range n.OperationTypes {
+		name, err := g.goVarName(ot.Type.Name.Value, ot.Type.Loc)
+		if err != nil {
+			return "", err
+		}
+		if ot.Operation == "query" {
+			out += "  Query: " + name + ",\n"
+		} else if ot.Operation == "mutation" {
+			out += "  Mutation: " + name + ",\n"
+		}
+	}
+	return out + "}\n", nil
+}
+
+// defaultSchema renders the "var schema = graphql.SchemaConfig{...}" block
+// used when the SDL declares its Query/Mutation types by convention
+// (types named "Query"/"Mutation") instead of an explicit "schema { ... }"
+// block.
+func (g *Generator) defaultSchema() string {
+	out := "var schema = graphql.SchemaConfig{\n"
+	if _, ok := g.defs["Query"]; ok {
+		out += "  Query: rootQuery,\n"
+	}
+	if _, ok := g.defs["Mutation"]; ok {
+		out += "  Mutation: rootMutation,\n"
+	}
+	return out + "}\n"
+}
+
+func (g *Generator) processObject(n *ast.ObjectDefinition) error {
+	varname := strings.ToLower(n.Name.Value) + "Object"
+	realname := n.Name.Value
+	resolve := false
+	if n.Name.Value == "Query" {
+		varname = "rootQuery"
+		realname = "RootQuery"
+		resolve = true
+	} else if n.Name.Value == "Mutation" {
+		varname = "rootMutation"
+		realname = "RootMutation"
+		resolve = true
+	}
+	out := "var " + varname + " = graphql.NewObject(graphql.ObjectConfig{\n\tName: \"" + realname + "\",\n"
+	out += descriptionField(n.Description)
+	out += "\tFields: graphql.Fields{\n"
+	g.deprecationReason(n.Directives, graphql.DirectiveLocationObject)
+	for _, f := range n.Fields {
+		if err := g.addField(varname, f, resolve); err != nil {
+			return err
+		}
+	}
+	g.varBlocks[n.Name.Value] = out + "}})\n"
+	return nil
+}
+
+func (g *Generator) addField(varname string, f *ast.FieldDefinition, resolve bool) error {
+	t, err := g.resolveType(f.Type)
+	if err != nil {
+		return err
+	}
+	out := fmt.Sprintf("\t%s.AddFieldConfig(\"%s\", &graphql.Field{\n", varname, f.Name.Value)
+	out += "\tType: " + t + ",\n"
+	out += descriptionField(f.Description)
+	if len(f.Arguments) > 0 {
+		out += "Args: graphql.FieldConfigArgument{\n"
+		for _, a := range f.Arguments {
+			at, err := g.resolveType(a.Type)
+			if err != nil {
+				return err
+			}
+			// ArgumentConfig has no DeprecationReason field; only track any
+			// non-builtin directives so they get registered below.
+			g.deprecationReason(a.Directives, graphql.DirectiveLocationArgumentDefinition)
+			dv, err := g.defaultValueField(a.DefaultValue, a.Type, a.Loc)
+			if err != nil {
+				return err
+			}
+			out += "\t\"" + a.Name.Value + "\": &graphql.ArgumentConfig{\n\t\tType: " + at + ",\n"
+			out += "\t\t" + descriptionField(a.Description)
+			out += "\t\t" + dv
+			out += "},\n"
+		}
+		out += "},\n"
+	}
+	if reason := g.deprecationReason(f.Directives, graphql.DirectiveLocationFieldDefinition); reason != "" {
+		out += "DeprecationReason: \"" + reason + "\",\n"
+	}
+	if resolve {
+		out += "Resolve: resolves[\"" + f.Name.Value + "\"],\n"
+	}
+	out += "})\n"
+	g.fields = append(g.fields, out)
+	return nil
+}
+
+// directiveDefs emits a package-level graphql.NewDirective var for every
+// non-builtin directive the schema used, in alphabetical order so runs are
+// reproducible.
+func (g *Generator) directiveDefs() string {
+	names := make([]string, 0, len(g.directives))
+	for name := range g.directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := ""
+	for _, name := range names {
+		out += fmt.Sprintf("var %sDirective = graphql.NewDirective(graphql.DirectiveConfig{\n\tName: %q,\n\tLocations: []string{%q},\n})\n", name, name, g.directives[name])
+	}
+	return out
+}
@@ -0,0 +1,292 @@
+package generator
+
+import (
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+func TestGenerateProcessScalar(t *testing.T) {
+	sdl := `
+scalar DateTime
+
+schema { query: Query }
+
+type Query {
+  now: DateTime
+}
+`
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(sdl)})})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out, err := NewGenerator().Generate(doc, "map")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !strings.Contains(out, `Name: "DateTime"`) {
+		t.Errorf("expected a DateTime scalar definition, got:\n%s", out)
+	}
+	if !strings.Contains(out, `Serialize: scalars["DateTime"].Serialize`) {
+		t.Errorf("expected the scalar to delegate to the scalars map, got:\n%s", out)
+	}
+	if _, err := format.Source([]byte(out)); err != nil {
+		t.Errorf("generated source doesn't parse as Go: %v\n%s", err, out)
+	}
+}
+
+func TestGenerateProcessEnum(t *testing.T) {
+	sdl := `
+schema { query: Query }
+
+enum Status {
+  ACTIVE
+  INACTIVE
+}
+
+type Query {
+  status: Status
+}
+`
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(sdl)})})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out, err := NewGenerator().Generate(doc, "map")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !strings.Contains(out, `"ACTIVE": &graphql.EnumValueConfig{
+		Value: 0,`) {
+		t.Errorf("expected ACTIVE to be assigned index 0, got:\n%s", out)
+	}
+	if !strings.Contains(out, `"INACTIVE": &graphql.EnumValueConfig{
+		Value: 1,`) {
+		t.Errorf("expected INACTIVE to be assigned index 1, got:\n%s", out)
+	}
+}
+
+// TestGenerateEnumDefaultInNonNullList is a regression test for the fix in
+// chunk0-6: a default value of enum members inside a non-null list type
+// ("[Status!]! = [ACTIVE, INACTIVE]") must still resolve each member to its
+// enum index, rather than silently emitting no default at all.
+func TestGenerateEnumDefaultInNonNullList(t *testing.T) {
+	sdl := `
+schema { query: Query }
+
+enum Status {
+  ACTIVE
+  INACTIVE
+}
+
+type Query {
+  search(statuses: [Status!]! = [ACTIVE, INACTIVE]): String
+}
+`
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(sdl)})})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out, err := NewGenerator().Generate(doc, "map")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !strings.Contains(out, "DefaultValue: []interface{}{0, 1},") {
+		t.Errorf("expected the default to resolve to enum indexes 0 and 1, got:\n%s", out)
+	}
+}
+
+func TestGenerateProcessInterfaceUnionInput(t *testing.T) {
+	sdl := `
+schema { query: Query }
+
+interface Node {
+  id: ID!
+}
+
+input SearchInput {
+  term: String!
+}
+
+type Cat implements Node {
+  id: ID!
+}
+
+type Dog implements Node {
+  id: ID!
+}
+
+union Pet = Cat | Dog
+
+type Query {
+  pet(filter: SearchInput): Pet
+  node: Node
+}
+`
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(sdl)})})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out, err := NewGenerator().Generate(doc, "map")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, want := range []string{
+		"graphql.NewInterface(graphql.InterfaceConfig{",
+		"graphql.NewInputObject(graphql.InputObjectConfig{",
+		"graphql.NewUnion(graphql.UnionConfig{",
+		"catObject,",
+		"dogObject,",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected generated source to contain %q, got:\n%s", want, out)
+		}
+	}
+	if _, err := format.Source([]byte(out)); err != nil {
+		t.Errorf("generated source doesn't parse as Go: %v\n%s", err, out)
+	}
+}
+
+// TestGenerateTypedNullableFieldIsNotDoublyIndirected is a regression test
+// for chunk0-5: a nullable field whose type is an object, interface, union
+// or custom scalar already renders as interface{}, which expresses "no
+// value" with nil on its own; goType must not wrap it in a second pointer.
+func TestGenerateTypedNullableFieldIsNotDoublyIndirected(t *testing.T) {
+	sdl := `
+schema { query: Query }
+
+type Owner {
+  name: String!
+}
+
+type Query {
+  greeting: String
+  owner: Owner
+}
+`
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(sdl)})})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out, err := NewGenerator().Generate(doc, "typed")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !strings.Contains(out, "Owner(ctx context.Context) (interface{}, error)") {
+		t.Errorf("expected a nullable object field to resolve as interface{}, not *interface{}, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Greeting(ctx context.Context) (*string, error)") {
+		t.Errorf("expected a nullable scalar field to stay pointer-typed, got:\n%s", out)
+	}
+	if _, err := format.Source([]byte(out)); err != nil {
+		t.Errorf("generated source doesn't parse as Go: %v\n%s", err, out)
+	}
+}
+
+// TestGenerateTypedModeCompilesAndRuns builds the -mode=typed output for a
+// schema with a nullable custom-scalar field into a standalone Go program,
+// wires it into a real graphql.Schema and executes a query against it. This
+// is the end-to-end path chunk0-5's *interface{} bug broke: graphql-go's
+// scalar Serialize hook received a **string-shaped value it couldn't type
+// assert, and panicked with "interface conversion: interface {} is
+// *interface {}, not string".
+func TestGenerateTypedModeCompilesAndRuns(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a scratch Go module; skipped in -short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	sdl := `
+scalar DateTime
+
+schema { query: Query }
+
+type Query {
+  now: DateTime
+}
+`
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(sdl)})})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out, err := NewGenerator().Generate(doc, "typed")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	src := strings.Replace(out, "package dummy", "package main", 1)
+	src += `
+type queryImpl struct{}
+
+func (queryImpl) Now(ctx context.Context) (interface{}, error) {
+	return "2026-07-30T00:00:00Z", nil
+}
+
+func main() {
+	resolvers := &QueryResolverAdapter{Resolver: queryImpl{}}
+	datetimeScalar := graphql.NewScalar(graphql.ScalarConfig{
+		Name:         "DateTime",
+		Serialize:    func(v interface{}) interface{} { return v.(string) },
+		ParseValue:   func(v interface{}) interface{} { return v },
+		ParseLiteral: func(v ast.Value) interface{} { return v.GetValue() },
+	})
+	rootQuery := graphql.NewObject(graphql.ObjectConfig{Name: "RootQuery", Fields: graphql.Fields{}})
+	rootQuery.AddFieldConfig("now", &graphql.Field{
+		Type:    datetimeScalar,
+		Resolve: resolvers.Resolvers()["now"],
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: rootQuery})
+	if err != nil {
+		panic(err)
+	}
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: "{ now }"})
+	if len(r.Errors) > 0 {
+		panic(r.Errors[0].Error())
+	}
+	data := r.Data.(map[string]interface{})
+	if data["now"] != "2026-07-30T00:00:00Z" {
+		panic("mismatch: " + data["now"].(string))
+	}
+}
+`
+	src = strings.Replace(src, `"github.com/graphql-go/graphql"`,
+		"\"github.com/graphql-go/graphql\"\n\t\"github.com/graphql-go/graphql/language/ast\"", 1)
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		t.Fatalf("generated program doesn't parse as Go: %v\n%s", err, src)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), formatted, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module gentypedcheck\n\ngo 1.21\n\nrequire github.com/graphql-go/graphql v0.8.1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), []byte(
+		"github.com/graphql-go/graphql v0.8.1 h1:p7/Ou/WpmulocJeEx7wjQy611rtXGQaAcXGqanuMMgc=\n"+
+			"github.com/graphql-go/graphql v0.8.1/go.mod h1:nKiHzRM0qopJEwCITUuIsxk9PlVlwIiiI8pnJEhordQ=\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bin := filepath.Join(dir, "typedcheck")
+	build := exec.Command("go", "build", "-o", bin, ".")
+	build.Dir = dir
+	build.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOSUMDB=off")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build of generated -mode=typed output failed: %v\n%s", err, out)
+	}
+
+	run := exec.Command(bin)
+	if out, err := run.CombinedOutput(); err != nil {
+		t.Fatalf("running the generated -mode=typed program failed: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,240 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// generateTyped renders, for every declared input type, a Go struct; and
+// for every declared object type, a gqlgen-style resolver interface plus an
+// Adapter that coerces graphql.ResolveParams into calls against it. The
+// Adapter's Resolvers method returns exactly the map[string]FieldResolveFn
+// getSchema already expects, so callers only need to change how that map
+// is built, not getSchema itself.
+func (g *Generator) generateTyped() (string, error) {
+	out := ""
+	for _, name := range g.topoOrder() {
+		if n, ok := g.defs[name].(*ast.InputObjectDefinition); ok {
+			block, err := g.inputStruct(n)
+			if err != nil {
+				return "", err
+			}
+			out += block
+		}
+	}
+	for _, name := range g.topoOrder() {
+		if n, ok := g.defs[name].(*ast.ObjectDefinition); ok {
+			block, err := g.resolverInterface(n)
+			if err != nil {
+				return "", err
+			}
+			out += block
+		}
+	}
+	return out, nil
+}
+
+// exportedName capitalizes the first letter of an SDL identifier so it can
+// be used as an exported Go identifier.
+func exportedName(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// goScalarType maps a built-in GraphQL scalar name to the Go type its
+// value decodes to in a graphql.ResolveParams.Args map.
+func goScalarType(name string) (goType string, ok bool) {
+	switch name {
+	case "String", "ID":
+		return "string", true
+	case "Int":
+		return "int", true
+	case "Float":
+		return "float64", true
+	case "Boolean":
+		return "bool", true
+	}
+	return "", false
+}
+
+// namedGoType resolves the Go type for a bare (unwrapped) named reference:
+// built-in scalars map to their natural Go type, enums decode to the same
+// int index processEnum assigns their values, input types become the
+// generated struct of the same name, and anything else (object, interface,
+// union, custom scalar) is left as interface{} since typed mode doesn't
+// generate models for them.
+func (g *Generator) namedGoType(n *ast.Named) (string, error) {
+	if s, ok := goScalarType(n.Name.Value); ok {
+		return s, nil
+	}
+	def, ok := g.defs[n.Name.Value]
+	if !ok {
+		return "", g.errorAt(n.Loc, "undefined type %q", n.Name.Value)
+	}
+	switch def.(type) {
+	case *ast.EnumDefinition:
+		return "int", nil
+	case *ast.InputObjectDefinition:
+		return n.Name.Value, nil
+	default:
+		return "interface{}", nil
+	}
+}
+
+// goType renders the Go type a typed resolver exposes for an SDL type
+// reference: non-null named types and lists are value types, a bare named
+// type is nullable. For built-in scalars, enums and input types that's
+// expressed with a pointer; object, interface, union and custom scalar
+// references already render as interface{},
+// which expresses "no value" with nil, so they're left unwrapped rather
+// than doubly-indirected through *interface{}.
+func (g *Generator) goType(t ast.Type) (string, error) {
+	switch n := t.(type) {
+	case *ast.NonNull:
+		return g.goTypeValue(n.Type)
+	case *ast.List:
+		inner, err := g.goType(n.Type)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + inner, nil
+	case *ast.Named:
+		inner, err := g.namedGoType(n)
+		if err != nil {
+			return "", err
+		}
+		if inner == "interface{}" {
+			return inner, nil
+		}
+		return "*" + inner, nil
+	}
+	return "", g.errorAt(nil, "unhandled type %T", t)
+}
+
+// goTypeValue is like goType but for a type already known to be non-null,
+// so a named reference renders as a value rather than a pointer.
+func (g *Generator) goTypeValue(t ast.Type) (string, error) {
+	switch n := t.(type) {
+	case *ast.List:
+		return g.goType(n)
+	case *ast.Named:
+		return g.namedGoType(n)
+	case *ast.NonNull:
+		return g.goTypeValue(n.Type)
+	}
+	return "", g.errorAt(nil, "unhandled type %T", t)
+}
+
+// inputStruct renders an input type as a Go struct with one json-tagged
+// field per SDL field.
+func (g *Generator) inputStruct(n *ast.InputObjectDefinition) (string, error) {
+	out := "type " + n.Name.Value + " struct {\n"
+	for _, f := range n.Fields {
+		t, err := g.goType(f.Type)
+		if err != nil {
+			return "", err
+		}
+		out += fmt.Sprintf("\t%s %s `json:%q`\n", exportedName(f.Name.Value), t, f.Name.Value)
+	}
+	return out + "}\n\n", nil
+}
+
+// argAssignment renders the Go type and the statements that extract and
+// coerce p.Args[argName] to it. Typed mode only supports the argument
+// shapes a plain method signature can express: a scalar or enum, bare or
+// non-null, and a non-null list of one.
+func (g *Generator) argAssignment(varName, argName string, t ast.Type, loc *ast.Location) (goType, code string, err error) {
+	nonNull := false
+	inner := t
+	if nn, ok := t.(*ast.NonNull); ok {
+		nonNull = true
+		inner = nn.Type
+	}
+	switch n := inner.(type) {
+	case *ast.Named:
+		scalar, err := g.namedGoType(n)
+		if err != nil {
+			return "", "", err
+		}
+		if nonNull {
+			return scalar, fmt.Sprintf("%s := p.Args[%q].(%s)\n", varName, argName, scalar), nil
+		}
+		code := fmt.Sprintf("var %s *%s\n", varName, scalar)
+		code += fmt.Sprintf("if v, ok := p.Args[%q]; ok && v != nil {\n\tvv := v.(%s)\n\t%s = &vv\n}\n", argName, scalar, varName)
+		return "*" + scalar, code, nil
+	case *ast.List:
+		elem, ok := n.Type.(*ast.Named)
+		if !ok {
+			if nn, ok2 := n.Type.(*ast.NonNull); ok2 {
+				elem, ok = nn.Type.(*ast.Named)
+			}
+		}
+		if !ok {
+			return "", "", g.errorAt(loc, "typed mode only supports list arguments of scalars or enums, argument %q is more nested than that", argName)
+		}
+		if !nonNull {
+			return "", "", g.errorAt(loc, "typed mode requires list argument %q to be non-null", argName)
+		}
+		scalar, err := g.namedGoType(elem)
+		if err != nil {
+			return "", "", err
+		}
+		code := fmt.Sprintf("var %s []%s\n", varName, scalar)
+		code += fmt.Sprintf("for _, item := range p.Args[%q].([]interface{}) {\n\t%s = append(%s, item.(%s))\n}\n", argName, varName, varName, scalar)
+		return "[]" + scalar, code, nil
+	}
+	return "", "", g.errorAt(loc, "typed mode does not support argument %q of this shape", argName)
+}
+
+// resolverInterface renders the gqlgen-style resolver interface for an
+// object type plus the Adapter that wraps an implementation of it into the
+// map[string]graphql.FieldResolveFn getSchema expects.
+func (g *Generator) resolverInterface(n *ast.ObjectDefinition) (string, error) {
+	resolverName := exportedName(n.Name.Value) + "Resolver"
+	adapterName := exportedName(n.Name.Value) + "ResolverAdapter"
+
+	iface := "type " + resolverName + " interface {\n"
+	adapter := "type " + adapterName + " struct {\n\tResolver " + resolverName + "\n}\n\n"
+	resolverMap := ""
+
+	for _, f := range n.Fields {
+		method := exportedName(f.Name.Value)
+		retType, err := g.goType(f.Type)
+		if err != nil {
+			return "", err
+		}
+
+		params := []string{"ctx context.Context"}
+		callArgs := []string{"p.Context"}
+		coerce := ""
+		for _, a := range f.Arguments {
+			argVar := a.Name.Value + "Arg"
+			argType, argCode, err := g.argAssignment(argVar, a.Name.Value, a.Type, a.Loc)
+			if err != nil {
+				return "", err
+			}
+			params = append(params, a.Name.Value+" "+argType)
+			callArgs = append(callArgs, argVar)
+			coerce += argCode
+		}
+
+		iface += "\t" + method + "(" + strings.Join(params, ", ") + ") (" + retType + ", error)\n"
+
+		adapter += "func (a *" + adapterName + ") " + method + "(p graphql.ResolveParams) (interface{}, error) {\n"
+		adapter += coerce
+		adapter += "\treturn a.Resolver." + method + "(" + strings.Join(callArgs, ", ") + ")\n}\n\n"
+
+		resolverMap += fmt.Sprintf("\t\t%q: a.%s,\n", f.Name.Value, method)
+	}
+	iface += "}\n\n"
+
+	adapter += "// Resolvers returns the field resolver map getSchema expects.\n"
+	adapter += "func (a *" + adapterName + ") Resolvers() map[string]graphql.FieldResolveFn {\n"
+	adapter += "\treturn map[string]graphql.FieldResolveFn{\n" + resolverMap + "\t}\n}\n\n"
+
+	return iface + adapter, nil
+}
@@ -0,0 +1,336 @@
+// Package httpserver mounts a built graphql.Schema behind an http.Handler,
+// with an optional GraphiQL IDE and a toggle to reject introspection
+// queries. It only depends on the schema value itself, so it works equally
+// well with a schema built by hand, by schema.MustBuildSchema, or by the
+// code this repo's generator emits.
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Config controls how a schema is exposed over HTTP.
+type Config struct {
+	// GraphiQL serves the GraphiQL IDE on browser GET requests that carry
+	// no query, instead of the usual "missing query" error.
+	GraphiQL bool
+	// GraphiQLPath, if non-empty, additionally serves the GraphiQL IDE at
+	// this path instead of (or as well as) negotiating it on the API
+	// endpoint itself. Only Mount can honor it, since it's the only entry
+	// point that knows both the API path and this one; NewHandler serves a
+	// single path and ignores it.
+	GraphiQLPath string
+	// Pretty indents JSON responses for easier manual inspection.
+	Pretty bool
+	// DisableIntrospection rejects any request selecting __schema or
+	// __type with a 403, hiding the schema from clients that shouldn't be
+	// able to introspect it.
+	DisableIntrospection bool
+	// CORSOrigins lists the Origin header values allowed to make
+	// cross-origin requests; "*" allows any origin. An empty list disables
+	// CORS handling, so no Access-Control-* headers are sent and browsers
+	// enforce same-origin as usual.
+	CORSOrigins []string
+	// AllowedVHosts lists the Host header values (without port) this
+	// handler will serve; "*" allows any host. An empty list allows any
+	// host, matching how a schema built without AllowedVHosts behaved
+	// before this check existed.
+	AllowedVHosts []string
+	// Timeout bounds how long a single request (including a batch) may run
+	// before graphql.Do's context is cancelled. Zero disables the timeout.
+	Timeout time.Duration
+}
+
+// requestBody is the shape of a single GraphQL-over-HTTP operation, whether
+// it arrives as a POST JSON object, one element of a POST JSON batch, or
+// GET query-string parameters.
+type requestBody struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// Mount registers schema's handler on mux at path. If cfg.GraphiQLPath is
+// set to a different path, it's also registered, serving the GraphiQL IDE
+// pointed at path rather than negotiating it on path itself.
+func Mount(mux *http.ServeMux, path string, schema graphql.Schema, cfg Config) {
+	mux.Handle(path, NewHandler(schema, cfg))
+	if cfg.GraphiQLPath != "" && cfg.GraphiQLPath != path {
+		mux.HandleFunc(cfg.GraphiQLPath, func(w http.ResponseWriter, r *http.Request) {
+			serveGraphiQL(w, path)
+		})
+	}
+}
+
+// NewHandler returns an http.Handler that executes requests against schema.
+// GET requests take query/variables/operationName from the URL query
+// string; POST requests take them from a JSON body.
+func NewHandler(schema graphql.Schema, cfg Config) http.Handler {
+	return &gqlHandler{schema: schema, cfg: cfg}
+}
+
+type gqlHandler struct {
+	schema graphql.Schema
+	cfg    Config
+}
+
+func (h *gqlHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.checkVHost(w, r) {
+		return
+	}
+	if h.handleCORS(w, r) {
+		return
+	}
+
+	if h.cfg.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), h.cfg.Timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+
+	reqs, batched, ok := h.parseRequests(w, r)
+	if !ok {
+		return
+	}
+
+	if !batched && reqs[0].Query == "" {
+		if h.cfg.GraphiQL && r.Method == http.MethodGet && acceptsHTML(r) {
+			serveGraphiQL(w, r.URL.Path)
+			return
+		}
+		http.Error(w, "missing query", http.StatusBadRequest)
+		return
+	}
+
+	results := make([]*graphql.Result, len(reqs))
+	for i, req := range reqs {
+		if h.cfg.DisableIntrospection && queriesIntrospection(req.Query) {
+			http.Error(w, "introspection is disabled", http.StatusForbidden)
+			return
+		}
+		results[i] = graphql.Do(graphql.Params{
+			Context:        r.Context(),
+			Schema:         h.schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	if h.cfg.Pretty {
+		enc.SetIndent("", "  ")
+	}
+	if batched {
+		enc.Encode(results)
+	} else {
+		enc.Encode(results[0])
+	}
+}
+
+// parseRequests reads one or more requestBody values from r, writing an
+// error response and returning ok=false if r is malformed or uses an
+// unsupported method. batched reports whether r carried a JSON array of
+// operations, which determines whether the response is an array too.
+func (h *gqlHandler) parseRequests(w http.ResponseWriter, r *http.Request) (reqs []requestBody, batched, ok bool) {
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		req := requestBody{Query: q.Get("query"), OperationName: q.Get("operationName")}
+		if vars := q.Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				http.Error(w, "invalid variables: "+err.Error(), http.StatusBadRequest)
+				return nil, false, false
+			}
+		}
+		return []requestBody{req}, false, true
+	case http.MethodPost:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "reading request body: "+err.Error(), http.StatusBadRequest)
+			return nil, false, false
+		}
+		if isGraphQLContentType(r) {
+			return []requestBody{{Query: string(body)}}, false, true
+		}
+		body = bytes.TrimSpace(body)
+		if len(body) > 0 && body[0] == '[' {
+			if err := json.Unmarshal(body, &reqs); err != nil {
+				http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+				return nil, false, false
+			}
+			return reqs, true, true
+		}
+		var req requestBody
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &req); err != nil {
+				http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+				return nil, false, false
+			}
+		}
+		return []requestBody{req}, false, true
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false, false
+	}
+}
+
+// isGraphQLContentType reports whether r's body is a raw query document
+// rather than a JSON request, per the application/graphql convention.
+func isGraphQLContentType(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/graphql")
+}
+
+// checkVHost rejects, with a 403, any request whose Host header isn't in
+// cfg.AllowedVHosts.
+func (h *gqlHandler) checkVHost(w http.ResponseWriter, r *http.Request) bool {
+	if len(h.cfg.AllowedVHosts) == 0 {
+		return true
+	}
+	host := r.Host
+	if hostOnly, _, err := net.SplitHostPort(r.Host); err == nil {
+		host = hostOnly
+	}
+	for _, allowed := range h.cfg.AllowedVHosts {
+		if allowed == "*" || allowed == host {
+			return true
+		}
+	}
+	http.Error(w, "invalid host header", http.StatusForbidden)
+	return false
+}
+
+// handleCORS sets CORS response headers when r's Origin is allowed by
+// cfg.CORSOrigins, and fully answers an OPTIONS preflight request itself.
+// It reports whether it already wrote the response (true only for a
+// handled preflight).
+func (h *gqlHandler) handleCORS(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || !h.originAllowed(origin) {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Vary", "Origin")
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+	return false
+}
+
+func (h *gqlHandler) originAllowed(origin string) bool {
+	for _, allowed := range h.cfg.CORSOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// queriesIntrospection reports whether query selects __schema or __type
+// anywhere in its operations. A query that fails to parse here is let
+// through unchanged; graphql.Do will reject it with the real parse error.
+func queriesIntrospection(query string) bool {
+	src := source.NewSource(&source.Source{Body: []byte(query), Name: "GraphQL request"})
+	doc, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		return false
+	}
+	for _, def := range doc.Definitions {
+		var set *ast.SelectionSet
+		switch d := def.(type) {
+		case *ast.OperationDefinition:
+			set = d.SelectionSet
+		case *ast.FragmentDefinition:
+			set = d.SelectionSet
+		}
+		if selectionsIntrospect(set) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectionsIntrospect walks a selection set looking for __schema or
+// __type fields, recursing into sub-selections and inline fragments.
+// Named fragment spreads are checked where they're defined, since
+// queriesIntrospection walks every top-level FragmentDefinition too.
+func selectionsIntrospect(set *ast.SelectionSet) bool {
+	if set == nil {
+		return false
+	}
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			if s.Name.Value == "__schema" || s.Name.Value == "__type" {
+				return true
+			}
+			if selectionsIntrospect(s.SelectionSet) {
+				return true
+			}
+		case *ast.InlineFragment:
+			if selectionsIntrospect(s.SelectionSet) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func acceptsHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+func serveGraphiQL(w http.ResponseWriter, endpoint string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, graphiqlPage, endpoint)
+}
+
+// graphiqlPage is a minimal GraphiQL host page pulling React and GraphiQL
+// from a CDN, pointed at whatever path the handler is mounted on.
+const graphiqlPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>GraphiQL</title>
+  <link href="https://unpkg.com/graphiql/graphiql.min.css" rel="stylesheet" />
+  <style>body { margin: 0; } #graphiql { height: 100vh; }</style>
+</head>
+<body>
+  <div id="graphiql">Loading GraphiQL...</div>
+  <script src="https://unpkg.com/react/umd/react.production.min.js"></script>
+  <script src="https://unpkg.com/react-dom/umd/react-dom.production.min.js"></script>
+  <script src="https://unpkg.com/graphiql/graphiql.min.js"></script>
+  <script>
+    function graphQLFetcher(params) {
+      return fetch(%q, {
+        method: 'post',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(params),
+      }).then(function (response) {
+        return response.json();
+      });
+    }
+    ReactDOM.render(
+      React.createElement(GraphiQL, { fetcher: graphQLFetcher }),
+      document.getElementById('graphiql'),
+    );
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,164 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func testSchema(t *testing.T) graphql.Schema {
+	t.Helper()
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"hello": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return "world", nil
+				},
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query})
+	if err != nil {
+		t.Fatalf("building test schema: %v", err)
+	}
+	return schema
+}
+
+func TestHandlerExecutesQuery(t *testing.T) {
+	h := NewHandler(testSchema(t), Config{})
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query={hello}", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"world"`) {
+		t.Errorf("body = %s, want it to contain %q", w.Body.String(), "world")
+	}
+}
+
+func TestHandlerMissingQuery(t *testing.T) {
+	h := NewHandler(testSchema(t), Config{})
+	req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandlerServesGraphiQL(t *testing.T) {
+	h := NewHandler(testSchema(t), Config{GraphiQL: true})
+	req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	req.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "GraphiQL") {
+		t.Errorf("body doesn't look like the GraphiQL page: %s", w.Body.String())
+	}
+}
+
+func TestHandlerDisablesIntrospection(t *testing.T) {
+	h := NewHandler(testSchema(t), Config{DisableIntrospection: true})
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query={__schema{types{name}}}", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d, body = %s", w.Code, http.StatusForbidden, w.Body.String())
+	}
+}
+
+func TestHandlerAllowsIntrospectionByDefault(t *testing.T) {
+	h := NewHandler(testSchema(t), Config{})
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query={__schema{queryType{name}}}", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandlerAcceptsApplicationGraphQLBody(t *testing.T) {
+	h := NewHandler(testSchema(t), Config{})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader("{hello}"))
+	req.Header.Set("Content-Type", "application/graphql")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"world"`) {
+		t.Errorf("body = %s, want it to contain %q", w.Body.String(), "world")
+	}
+}
+
+func TestHandlerExecutesBatchedQueries(t *testing.T) {
+	h := NewHandler(testSchema(t), Config{})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`[{"query":"{hello}"},{"query":"{hello}"}]`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if strings.Count(w.Body.String(), `"world"`) != 2 {
+		t.Errorf("body = %s, want two results containing %q", w.Body.String(), "world")
+	}
+}
+
+func TestHandlerRejectsDisallowedVHost(t *testing.T) {
+	h := NewHandler(testSchema(t), Config{AllowedVHosts: []string{"api.example.com"}})
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query={hello}", nil)
+	req.Host = "evil.example.com"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestMountServesGraphiQLAtSeparatePath(t *testing.T) {
+	mux := http.NewServeMux()
+	Mount(mux, "/graphql", testSchema(t), Config{GraphiQLPath: "/graphiql"})
+
+	req := httptest.NewRequest(http.MethodGet, "/graphiql", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "GraphiQL") {
+		t.Errorf("body doesn't look like the GraphiQL page: %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "/graphql") {
+		t.Errorf("body = %s, want the fetcher pointed at the API path %q", w.Body.String(), "/graphql")
+	}
+}
+
+func TestHandlerSetsCORSHeaderForAllowedOrigin(t *testing.T) {
+	h := NewHandler(testSchema(t), Config{CORSOrigins: []string{"https://example.com"}})
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query={hello}", nil)
+	req.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, "https://example.com")
+	}
+}
@@ -61,6 +61,10 @@ const (
 	itemRightBracket       // ']'
 	itemSpace              // run of spaces separating arguments
 	itemStringValue        // String value enclosed by """ and """
+	itemAt                 // @
+	itemAmpersand          // &
+	itemPipe               // | separating union members
+	itemUnionEnd           // line break (or EOF) ending a union declaration
 
 	itemBlockStart // Definition block start
 	itemBlockEnd   // Definition block end
@@ -93,6 +97,10 @@ var LexNames = map[Token]string{
 	itemRightBracket: "]",
 	itemSpace:        "space",
 	itemStringValue:  "String Value",
+	itemAt:           "@",
+	itemAmpersand:    "&",
+	itemPipe:         "|",
+	itemUnionEnd:     "union end",
 
 	itemBlockStart: "block start",
 	itemBlockEnd:   "block end",
@@ -128,41 +136,52 @@ const eof = -1
 // stateFn represents the state of the scanner as a function that returns the next state.
 type stateFn func(*lexer) stateFn
 
-var fnStack = []stateFn{}
+// lexer holds the state of the scanner.
+type lexer struct {
+	name       string    // the name of the input; used only for error reports
+	input      string    // the string being scanned
+	pos        Pos       // current position in the input
+	start      Pos       // start position of this item
+	width      Pos       // width of last rune read from input
+	items      []item    // scanned items, filled by run before nextItem is called
+	itemPos    int       // read cursor into items, advanced by nextItem
+	fnStack    []stateFn // stack of states to return to once the current one pops
+	parenDepth int       // nesting depth of ( ) exprs
+	line       int       // 1+number of newlines seen
+}
 
-func push(f stateFn) {
-	ln := len(fnStack)
-	if ln > 0 && reflect.ValueOf(fnStack[ln-1]) == reflect.ValueOf(f) {
+// push remembers f as the state to return to once the state it interrupts
+// is done, unless f is already on top of the stack.
+func (l *lexer) push(f stateFn) {
+	ln := len(l.fnStack)
+	if ln > 0 && reflect.ValueOf(l.fnStack[ln-1]) == reflect.ValueOf(f) {
 		return
 	}
-	fnStack = append(fnStack, f)
+	l.fnStack = append(l.fnStack, f)
 }
 
-func pop() stateFn {
-	ln := len(fnStack)
+// pop removes and returns the state on top of the stack.
+func (l *lexer) pop() stateFn {
+	ln := len(l.fnStack)
 	if ln == 0 {
 		return nil
 	}
-	last := fnStack[ln-1]
-	fnStack = fnStack[:ln-1]
+	last := l.fnStack[ln-1]
+	l.fnStack = l.fnStack[:ln-1]
 	return last
 }
 
-func last() stateFn {
-	ln := len(fnStack)
-	return fnStack[ln-1]
+// atState reports whether f is the state that resumes once the scan
+// currently in progress pops, i.e. the one that called into it.
+func (l *lexer) atState(f stateFn) bool {
+	ln := len(l.fnStack)
+	return ln > 0 && reflect.ValueOf(l.fnStack[ln-1]) == reflect.ValueOf(f)
 }
 
-// lexer holds the state of the scanner.
-type lexer struct {
-	name       string    // the name of the input; used only for error reports
-	input      string    // the string being scanned
-	pos        Pos       // current position in the input
-	start      Pos       // start position of this item
-	width      Pos       // width of last rune read from input
-	items      chan item // channel of scanned items
-	parenDepth int       // nesting depth of ( ) exprs
-	line       int       // 1+number of newlines seen
+// last returns the state on top of the stack without removing it.
+func (l *lexer) last() stateFn {
+	ln := len(l.fnStack)
+	return l.fnStack[ln-1]
 }
 
 // next returns the next rune in the input.
@@ -196,9 +215,9 @@ func (l *lexer) backup() {
 	}
 }
 
-// emit passes an item back to the client.
+// emit appends an item to the buffer nextItem will later read from.
 func (l *lexer) emit(t Token) {
-	l.items <- item{t, l.start, l.input[l.start:l.pos], l.line}
+	l.items = append(l.items, item{t, l.start, l.input[l.start:l.pos], l.line})
 	// Some items contain text internally. If so, count their newlines.
 	switch t {
 	case itemStringValue:
@@ -229,44 +248,48 @@ func (l *lexer) acceptRun(valid string) {
 	l.backup()
 }
 
-// errorf returns an error token and terminates the scan by passing
-// back a nil pointer that will be the next state, terminating l.nextItem.
+// errorf appends an error item and terminates the scan by returning a nil
+// state, stopping run's loop.
 func (l *lexer) errorf(format string, args ...interface{}) stateFn {
-	l.items <- item{itemError, l.start, fmt.Sprintf(format, args...), l.line}
+	l.items = append(l.items, item{itemError, l.start, fmt.Sprintf(format, args...), l.line})
 	return nil
 }
 
-// nextItem returns the next item from the input.
-// Called by the parser, not in the lexing goroutine.
+// nextItem returns the next item from the input. Called by the parser.
 func (l *lexer) nextItem() item {
-	return <-l.items
+	if l.itemPos >= len(l.items) {
+		return item{typ: itemEOF, pos: l.pos, line: l.line}
+	}
+	it := l.items[l.itemPos]
+	l.itemPos++
+	return it
 }
 
-// drain drains the output so the lexing goroutine will exit.
-// Called by the parser, not in the lexing goroutine.
+// drain discards any items the parser didn't consume. The whole input is
+// lexed up front, so unlike the old channel-based lexer this can't leak a
+// goroutine; it's kept so callers that bail out early don't need to care
+// either way.
 func (l *lexer) drain() {
-	for range l.items {
-	}
+	l.itemPos = len(l.items)
 }
 
-// lex creates a new scanner for the input string.
+// lex scans the entire input string and returns a lexer ready for
+// nextItem to read its items back in order.
 func lex(name, input string) *lexer {
 	l := &lexer{
 		name:  name,
 		input: input,
-		items: make(chan item),
 		line:  1,
 	}
-	go l.run()
+	l.run()
 	return l
 }
 
-// run runs the state machine for the lexer.
+// run runs the state machine for the lexer, filling l.items.
 func (l *lexer) run() {
 	for state := lexSchema; state != nil; {
 		state = state(l)
 	}
-	close(l.items)
 }
 
 // state functions
@@ -288,6 +311,11 @@ func isAlphaNumeric(r rune) bool {
 	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
 }
 
+// isDigit reports whether r is a decimal digit.
+func isDigit(r rune) bool {
+	return unicode.IsDigit(r)
+}
+
 // atTerminator reports whether the input is at valid termination character to
 // appear after an identifier. Breaks .X.Y into two pieces. Also catches cases
 // like "$x+2" not being acceptable without a space, in case we decide one
@@ -298,7 +326,7 @@ func (l *lexer) atTerminator() bool {
 		return true
 	}
 	switch r {
-	case eof, ':', ')', '(', ',', ']':
+	case eof, ':', ')', '(', ',', ']', '!':
 		return true
 	}
 	return false
@@ -308,7 +336,10 @@ func (l *lexer) atTerminator() bool {
 
 // lexSchema is outside of any definition, default state
 func lexSchema(l *lexer) stateFn {
-	push(lexSchema)
+	l.push(lexSchema)
+	if strings.HasPrefix(l.input[l.pos:], "\"\"\"") {
+		return lexStringValue
+	}
 	switch r := l.next(); {
 	case r == eof:
 		l.emit(itemEOF)
@@ -318,10 +349,15 @@ func lexSchema(l *lexer) stateFn {
 		return lexSchema // no need to handle spaces
 	case r == '#':
 		return lexComment
+	case r == '"':
+		return lexSingleLineStringValue
 	case r == '{':
 		l.ignore()
 		l.emit(itemBlockStart)
 		return lexBlock
+	case r == '&':
+		l.emit(itemAmpersand)
+		return lexSchema
 	case isAlphaNumeric(r):
 		l.backup()
 		return lexIdentifier
@@ -335,7 +371,7 @@ func lexComment(l *lexer) stateFn {
 	for {
 		switch r := l.next(); {
 		case r == eof || isEndOfLine(r):
-			return last()
+			return l.last()
 		}
 		l.ignore()
 	}
@@ -348,6 +384,8 @@ Loop:
 		switch r := l.next(); {
 		case isAlphaNumeric(r):
 			// absorb.
+		case r == '.' && isDigit(l.peek()):
+			// absorb the fractional part of a float default value, e.g. 3.14.
 		default:
 			l.backup()
 			word := l.input[l.start:l.pos]
@@ -355,6 +393,16 @@ Loop:
 				return l.errorf("bad character %#U", r)
 			}
 			switch {
+			case word == "union" && l.atState(lexSchema):
+				// Only the top-level "union SearchResult = ..." declaration
+				// enters the union-member grammar; a field, argument or
+				// type elsewhere in the schema may still be named "union".
+				l.emit(itemUnion)
+				return lexUnionMembers
+			case word == "union":
+				// Not in a position where "union" starts a declaration, so
+				// it's just an identifier (a field or argument named union).
+				l.emit(itemIdentifier)
 			case key[word] > itemKeyword:
 				l.emit(key[word])
 			default:
@@ -363,11 +411,11 @@ Loop:
 			break Loop
 		}
 	}
-	return last()
+	return l.last()
 }
 
 func lexProperty(l *lexer) stateFn {
-	push(lexProperty)
+	l.push(lexProperty)
 	startPos := l.pos
 	for {
 		switch r := l.next(); {
@@ -381,16 +429,59 @@ func lexProperty(l *lexer) stateFn {
 				return l.errorf("no value at %v", l.pos)
 			}
 			l.ignore()
-			pop()
-			return last()
+			l.pop()
+			return l.last()
 		default:
 			return l.errorf("unterminated property %v", string(r))
 		}
 	}
 }
 
+// lexDirective scans the name following an '@' marker. The leading '@' has
+// already been emitted as itemAt. After a field or enum value, an argument
+// list that follows the name is handled by lexArgs like any other
+// parenthesised argument list; inside a field's argument list, lexArgs has
+// no case for '(', so a directive there is lexed by name only and cannot
+// take arguments.
+func lexDirective(l *lexer) stateFn {
+	r := l.next()
+	if !isAlphaNumeric(r) {
+		return l.errorf("expected directive name after '@', got %#U", r)
+	}
+	l.backup()
+	return lexIdentifier
+}
+
+// lexUnionMembers scans a union declaration's name, its '=', and the
+// '|'-separated list of member type names, up to and including the line
+// break (or EOF) that ends it. The 'union' keyword has already been emitted.
+func lexUnionMembers(l *lexer) stateFn {
+	l.push(lexUnionMembers)
+	for {
+		switch r := l.next(); {
+		case isAlphaNumeric(r):
+			l.backup()
+			return lexIdentifier
+		case isSpace(r):
+			l.ignore()
+		case r == '#':
+			return lexComment
+		case r == '=':
+			l.emit(itemEqual)
+		case r == '|':
+			l.emit(itemPipe)
+		case r == eof || isEndOfLine(r):
+			l.emit(itemUnionEnd)
+			l.pop()
+			return l.last()
+		default:
+			return l.errorf("unexpected character in union declaration %#U", r)
+		}
+	}
+}
+
 func lexArgs(l *lexer) stateFn {
-	push(lexArgs)
+	l.push(lexArgs)
 	startLine := l.line
 	for {
 		switch r := l.next(); {
@@ -414,6 +505,13 @@ func lexArgs(l *lexer) stateFn {
 			l.emit(itemComma)
 		case r == '!':
 			l.emit(itemExclamation)
+		case r == '=':
+			l.emit(itemEqual)
+		case r == '"':
+			return lexSingleLineStringValue
+		case r == '@':
+			l.emit(itemAt)
+			return lexDirective
 		case r == '[':
 			l.emit(itemLeftBracket)
 			l.parenDepth++
@@ -423,16 +521,20 @@ func lexArgs(l *lexer) stateFn {
 			if l.parenDepth < 0 {
 				return l.errorf("unexpected right bracket %#U", r)
 			}
+		case r == '{':
+			// An object literal default value, e.g. `filter: Filter = {status: ACTIVE}`.
+			l.emit(itemBlockStart)
+			return lexBlock
 		case r == ')':
 			l.emit(itemRightParen)
-			pop()
-			return last()
+			l.pop()
+			return l.last()
 		}
 	}
 }
 
 func lexBlock(l *lexer) stateFn {
-	push(lexBlock)
+	l.push(lexBlock)
 	startLine := l.line
 	for {
 		if strings.HasPrefix(l.input[l.pos:], "\"\"\"") {
@@ -459,6 +561,11 @@ func lexBlock(l *lexer) stateFn {
 			l.emit(itemExclamation)
 		case r == '=':
 			l.emit(itemEqual)
+		case r == '"':
+			return lexSingleLineStringValue
+		case r == '@':
+			l.emit(itemAt)
+			return lexDirective
 		case r == '(':
 			l.emit(itemLeftParen)
 			return lexArgs
@@ -474,8 +581,8 @@ func lexBlock(l *lexer) stateFn {
 		case r == '}':
 			l.ignore()
 			l.emit(itemBlockEnd)
-			pop()
-			return last()
+			l.pop()
+			return l.last()
 		}
 	}
 }
@@ -491,5 +598,26 @@ func lexStringValue(l *lexer) stateFn {
 	l.pos += Pos(i)
 	l.emit(itemStringValue)
 	l.pos += Pos(3)
-	return last()
+	return l.last()
+}
+
+// lexSingleLineStringValue scans a single-line "..." string value, the
+// short form of a description. The opening quote is known to be present
+// and already consumed.
+func lexSingleLineStringValue(l *lexer) stateFn {
+	l.ignore() // drop the opening quote
+	for {
+		switch r := l.next(); r {
+		case eof, '\n':
+			return l.errorf("unterminated string at pos %v", l.pos)
+		case '\\':
+			l.next() // skip the escaped character
+		case '"':
+			l.backup()
+			l.emit(itemStringValue)
+			l.pos++ // skip the closing quote
+			l.ignore()
+			return l.last()
+		}
+	}
 }
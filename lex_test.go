@@ -2,6 +2,7 @@ package schema
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -12,7 +13,7 @@ type lexTest struct {
 }
 
 // Make the types prettyprint.
-var itemName = map[token]string{
+var itemName = map[Token]string{
 	itemError:       "error",
 	itemEOF:         "EOF",
 	itemColon:       ":",
@@ -32,10 +33,11 @@ var (
 	tLpar       = mkItem(itemLeftParen, "(")
 	tRpar       = mkItem(itemRightParen, ")")
 	tType       = mkItem(itemType, "type")
+	tAt         = mkItem(itemAt, "@")
 	comment     = "# comment"
 )
 
-func (i token) String() string {
+func (i Token) String() string {
 	s := itemName[i]
 	if s == "" {
 		return fmt.Sprintf("item%d", int(i))
@@ -43,7 +45,7 @@ func (i token) String() string {
 	return s
 }
 
-func mkItem(typ token, text string) item {
+func mkItem(typ Token, text string) item {
 	return item{
 		typ: typ,
 		val: text,
@@ -160,6 +162,142 @@ var lexTests = []lexTest{
 			tEOF,
 		},
 	},
+	{
+		"union used as a field and argument name",
+		`type Query {
+			union(union: String): String
+		}`,
+		[]item{tType,
+			mkItem(itemIdentifier, "Query"),
+			tBlockStart,
+			mkItem(itemIdentifier, "union"),
+			tLpar,
+			mkItem(itemIdentifier, "union"),
+			tColon,
+			mkItem(itemIdentifier, "String"),
+			tRpar,
+			tColon,
+			mkItem(itemIdentifier, "String"),
+			tBlockEnd,
+			tEOF,
+		},
+	},
+	{
+		"field directive with arguments",
+		`type User {
+			name: String @deprecated(reason: "use fullName")
+		}`,
+		[]item{tType,
+			mkItem(itemIdentifier, "User"),
+			tBlockStart,
+			mkItem(itemIdentifier, "name"),
+			tColon,
+			mkItem(itemIdentifier, "String"),
+			tAt,
+			mkItem(itemIdentifier, "deprecated"),
+			tLpar,
+			mkItem(itemIdentifier, "reason"),
+			tColon,
+			mkItem(itemStringValue, "use fullName"),
+			tRpar,
+			tBlockEnd,
+			tEOF,
+		},
+	},
+	{
+		"single-line description",
+		`type User {
+			"Full name"
+			name: String
+		}`,
+		[]item{tType,
+			mkItem(itemIdentifier, "User"),
+			tBlockStart,
+			mkItem(itemStringValue, "Full name"),
+			mkItem(itemIdentifier, "name"),
+			tColon,
+			mkItem(itemIdentifier, "String"),
+			tBlockEnd,
+			tEOF,
+		},
+	},
+	{
+		"non-null modifier with no leading space",
+		"type Query {\n\tname: String!\n\ttags: [String!]!\n}",
+		[]item{tType,
+			mkItem(itemIdentifier, "Query"),
+			tBlockStart,
+			mkItem(itemIdentifier, "name"),
+			tColon,
+			mkItem(itemIdentifier, "String"),
+			mkItem(itemExclamation, "!"),
+			mkItem(itemIdentifier, "tags"),
+			tColon,
+			mkItem(itemLeftBracket, "["),
+			mkItem(itemIdentifier, "String"),
+			mkItem(itemExclamation, "!"),
+			mkItem(itemRightBracket, "]"),
+			mkItem(itemExclamation, "!"),
+			tBlockEnd,
+			tEOF,
+		},
+	},
+	{
+		"scalar declaration and argument default value",
+		`scalar UUID
+		type Query {
+			greet(name: String = "World"): String
+		}`,
+		[]item{
+			mkItem(itemScalar, "scalar"),
+			mkItem(itemIdentifier, "UUID"),
+			tType,
+			mkItem(itemIdentifier, "Query"),
+			tBlockStart,
+			mkItem(itemIdentifier, "greet"),
+			tLpar,
+			mkItem(itemIdentifier, "name"),
+			tColon,
+			mkItem(itemIdentifier, "String"),
+			mkItem(itemEqual, "="),
+			mkItem(itemStringValue, "World"),
+			tRpar,
+			tColon,
+			mkItem(itemIdentifier, "String"),
+			tBlockEnd,
+			tEOF,
+		},
+	},
+	{
+		"implements with ampersand",
+		`interface Named {
+			name: String
+		}
+		type User implements Named & Entity {
+			name: String
+		}`,
+		[]item{
+			mkItem(itemInterface, "interface"),
+			mkItem(itemIdentifier, "Named"),
+			tBlockStart,
+			mkItem(itemIdentifier, "name"),
+			tColon,
+			mkItem(itemIdentifier, "String"),
+			tBlockEnd,
+			tType,
+			mkItem(itemIdentifier, "User"),
+			mkItem(itemImplements, "implements"),
+			mkItem(itemIdentifier, "Named"),
+			mkItem(itemAmpersand, "&"),
+			mkItem(itemIdentifier, "Entity"),
+			tBlockStart,
+			mkItem(itemIdentifier, "name"),
+			tColon,
+			mkItem(itemIdentifier, "String"),
+			tBlockEnd,
+			tEOF,
+		},
+	},
 }
 
 func TestLex(t *testing.T) {
@@ -171,6 +309,38 @@ func TestLex(t *testing.T) {
 	}
 }
 
+// largeSchema builds a fixed multi-thousand-line SDL document: a single
+// Query type with n fields, each taking an argument and documented with a
+// description, so BenchmarkLex exercises identifiers, arguments, types and
+// descriptions at a scale a hand-written lexTest entry can't reach.
+func largeSchema(n int) string {
+	var b strings.Builder
+	b.WriteString("type Query {\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "  \"\"\"Looks up item %d by id.\"\"\"\n", i)
+		fmt.Fprintf(&b, "  item%d(id: ID!): String\n", i)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// BenchmarkLex measures the cost of lexing a multi-thousand-line schema,
+// including the up-front scan lex now does instead of handing items off
+// lazily through a channel.
+func BenchmarkLex(b *testing.B) {
+	input := largeSchema(5000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l := lex("benchmark", input)
+		for {
+			item := l.nextItem()
+			if item.typ == itemEOF || item.typ == itemError {
+				break
+			}
+		}
+	}
+}
+
 // collect gathers the emitted items into a slice.
 func collect(t *lexTest) (items []item) {
 	l := lex(t.name, t.input)
@@ -3,19 +3,27 @@ package schema
 import (
 	"fmt"
 	"runtime"
+	"strconv"
 
 	"github.com/graphql-go/graphql"
 )
 
 type parseContext struct {
-	lex       *lexer
-	funcs     map[string]graphql.FieldResolveFn
-	scalars   map[string]graphql.Type
-	objects   map[string]*graphql.Object
-	unions    map[string]*graphql.Union
-	Root      *parseContext
-	token     [10]item
-	peekCount int
+	lex               *lexer
+	funcs             map[string]graphql.FieldResolveFn
+	resolveTypes      map[string]graphql.ResolveTypeFn
+	scalars           map[string]graphql.Type
+	customScalars     map[string]*graphql.Scalar
+	directiveHandlers map[string]DirectiveHandler
+	directives        map[string][]Directive
+	objects           map[string]*graphql.Object
+	unions            map[string]*graphql.Union
+	enums             map[string]*graphql.Enum
+	interfaces        map[string]*graphql.Interface
+	inputs            map[string]*graphql.InputObject
+	Root              *parseContext
+	token             [10]item
+	peekCount         int
 }
 
 // Funcs declared functions
@@ -30,38 +38,161 @@ var builtinscalars = map[string]graphql.Type{
 	"Boolean": graphql.Boolean,
 }
 
+// Directive is a parsed `@name(arg: literal, ...)` annotation on a field or
+// enum value. @deprecated is handled specially (see DeprecationReason on the
+// resulting graphql.Field/graphql.EnumValueConfig); every other directive is
+// collected into SchemaResult.Directives and, when it is on a field and a
+// handler was registered for its name, also passed to that handler.
+// Directives on enum values are only collected.
+type Directive struct {
+	Name string
+	Args map[string]interface{}
+}
+
+// DirectiveHandler reacts to a non-deprecation directive on a field, such as
+// `@auth` or `@cacheControl`, after the field has otherwise been built.
+type DirectiveHandler func(name string, args map[string]interface{}, field *graphql.Field)
+
+// SchemaResult is BuildSchemaConfig's return value. Directives collects every
+// non-deprecation directive the schema declared on a field or enum value,
+// keyed by "TypeName.fieldName" (or "EnumName.VALUE").
+type SchemaResult struct {
+	graphql.SchemaConfig
+	Directives map[string][]Directive
+}
+
 // Parsing.
 
-// MustBuildSchema is equivalent of buildSchema() in graphql.org example implementation
-func MustBuildSchema(schema string, resolvers map[string]graphql.FieldResolveFn) graphql.Schema {
-	schemaConfig, _ := BuildSchemaConfig(schema, resolvers)
-	newSchema, _ := graphql.NewSchema(schemaConfig)
+// MustBuildSchema is equivalent of buildSchema() in graphql.org example implementation.
+// resolveTypes supplies the ResolveType callback for each declared interface, keyed by
+// interface name; pass nil if the schema declares no interfaces. scalars supplies the
+// serialize/parseValue/parseLiteral behaviour for each `scalar Name` declaration, keyed
+// by scalar name; pass nil if the schema declares no custom scalars. directives supplies
+// a handler for each non-deprecation directive name the schema may use; pass nil if none
+// are needed.
+func MustBuildSchema(schema string, resolvers map[string]graphql.FieldResolveFn, resolveTypes map[string]graphql.ResolveTypeFn, scalars map[string]*graphql.Scalar, directives map[string]DirectiveHandler) graphql.Schema {
+	result, _ := BuildSchemaConfig(schema, resolvers, resolveTypes, scalars, directives)
+	newSchema, _ := graphql.NewSchema(result.SchemaConfig)
 	return newSchema
 }
 
-// BuildSchemaConfig is creating a graphql.SchemaConfig from a given string
-func BuildSchemaConfig(schema string, resolvers map[string]graphql.FieldResolveFn) (graphql.SchemaConfig, error) {
+// BuildSchemaConfig is creating a graphql.SchemaConfig from a given string.
+// resolveTypes supplies the ResolveType callback for each declared interface, keyed by
+// interface name; pass nil if the schema declares no interfaces. scalars supplies the
+// serialize/parseValue/parseLiteral behaviour for each `scalar Name` declaration, keyed
+// by scalar name; pass nil if the schema declares no custom scalars. directives supplies
+// a handler for each non-deprecation directive name the schema may use; pass nil if none
+// are needed. See SchemaResult for how directives surface to the caller.
+func BuildSchemaConfig(schema string, resolvers map[string]graphql.FieldResolveFn, resolveTypes map[string]graphql.ResolveTypeFn, scalars map[string]*graphql.Scalar, directives map[string]DirectiveHandler) (result SchemaResult, err error) {
 	funcs = resolvers
-	schemaConfig := graphql.SchemaConfig{}
+	result = SchemaResult{Directives: map[string][]Directive{}}
 	t := &parseContext{
-		lex:     lex("", schema),
-		scalars: builtinscalars,
-		objects: map[string]*graphql.Object{},
-		unions:  map[string]*graphql.Union{},
+		lex:               lex("", schema),
+		resolveTypes:      resolveTypes,
+		scalars:           builtinscalars,
+		customScalars:     scalars,
+		directiveHandlers: directives,
+		directives:        result.Directives,
+		objects:           map[string]*graphql.Object{},
+		unions:            map[string]*graphql.Union{},
+		enums:             map[string]*graphql.Enum{},
+		interfaces:        map[string]*graphql.Interface{},
+		inputs:            map[string]*graphql.InputObject{},
 	}
+	defer t.recover(&err)
 	t.backup()
+	var rootOps map[string]string
+	var desc string
 	for {
 		n := t.next()
 		switch {
 		case n.typ == itemEOF:
-			return schemaConfig, nil
+			t.applyRootOps(&result.SchemaConfig, rootOps)
+			// Register every declared object explicitly: one implementing an
+			// interface but never used as a field type (e.g. only reachable
+			// through that interface's ResolveType) would otherwise be
+			// invisible to the schema and reject fragments naming it.
+			for _, obj := range t.objects {
+				result.SchemaConfig.Types = append(result.SchemaConfig.Types, obj)
+			}
+			return result, nil
+		case n.typ == itemStringValue:
+			// A top-level string is a description, buffered for whichever
+			// declaration follows it.
+			desc = n.val
+			continue
 		case n.typ == itemType:
-			t.processTypeNode(&schemaConfig)
+			t.processTypeNode(&result.SchemaConfig, desc)
 		case n.typ == itemUnion:
-			t.processUnionNode()
+			t.processUnionNode(desc)
+		case n.typ == itemSchema:
+			rootOps = t.processSchemaNode()
+		case n.typ == itemEnum:
+			t.processEnumNode(desc)
+		case n.typ == itemInterface:
+			t.processInterfaceNode(desc)
+		case n.typ == itemInput:
+			t.processInputNode(desc)
+		case n.typ == itemScalar:
+			t.processScalarNode()
+		}
+		desc = ""
+	}
+
+}
+
+// processSchemaNode parses an explicit `schema { query: X, mutation: Y,
+// subscription: Z }` block and returns the operation -> type name mapping
+// it declares, for applyRootOps to resolve once every type is known.
+func (t *parseContext) processSchemaNode() map[string]string {
+	x := t.next()
+	if x.typ != itemBlockStart {
+		t.errorf("No block starting after schema keyword, got t: %#v, v: %#v", LexNames[x.typ], x.val)
+	}
+	ops := map[string]string{}
+	for {
+		x = t.next()
+		if x.typ == itemBlockEnd {
+			return ops
+		}
+		if x.typ != itemIdentifier {
+			t.errorf("No operation name after block start, got t: %#v, v: %#v", LexNames[x.typ], x.val)
+		}
+		op := x.val
+		if op != "query" && op != "mutation" && op != "subscription" {
+			t.errorf("Unknown root operation type %q in schema block", op)
+		}
+		x = t.next()
+		if x.typ != itemColon {
+			t.errorf("No colon after %s, got t: %#v, v: %#v", op, LexNames[x.typ], x.val)
+		}
+		x = t.next()
+		if x.typ != itemIdentifier {
+			t.errorf("No type identifier after %s:, got t: %#v, v: %#v", op, LexNames[x.typ], x.val)
 		}
+		ops[op] = x.val
 	}
+}
 
+// applyRootOps remaps schemaConfig's root operation types per an explicit
+// schema block, if the schema declared one. Referenced type names must
+// already be registered in t.objects by the time the whole schema has been
+// scanned, same as any other forward reference in this parser.
+func (t *parseContext) applyRootOps(schemaConfig *graphql.SchemaConfig, ops map[string]string) {
+	for op, name := range ops {
+		obj, ok := t.objects[name]
+		if !ok {
+			t.errorf("schema block references undeclared type %q", name)
+		}
+		switch op {
+		case "query":
+			schemaConfig.Query = obj
+		case "mutation":
+			schemaConfig.Mutation = obj
+		case "subscription":
+			schemaConfig.Subscription = obj
+		}
+	}
 }
 
 // dumpTokens is only used for debugging
@@ -75,7 +206,7 @@ func (t *parseContext) dumpTokens() {
 	}
 }
 
-func (t *parseContext) processUnionNode() {
+func (t *parseContext) processUnionNode(desc string) {
 	n := t.next()
 	if n.typ != itemIdentifier {
 		t.errorf("No identifier after union keyword, got t: %#v, v: %#v", LexNames[n.typ], n.val)
@@ -115,29 +246,108 @@ Loop:
 
 	t.unions[n.val] = graphql.NewUnion(
 		graphql.UnionConfig{
-			Name:  n.val,
-			Types: types,
+			Name:        n.val,
+			Types:       types,
+			Description: desc,
 		},
 	)
 }
 
-func (t *parseContext) processTypeNode(schemaConfig *graphql.SchemaConfig) {
+// processScalarNode parses a `scalar Name` declaration. The actual
+// serialize/parseValue/parseLiteral behaviour isn't declarable in SDL, so it
+// must come from the customScalars map passed to BuildSchemaConfig; an
+// undeclared one is a hard error rather than a silent pass-through scalar.
+func (t *parseContext) processScalarNode() {
+	n := t.next()
+	if n.typ != itemIdentifier {
+		t.errorf("No identifier after scalar keyword, got t: %#v, v: %#v", LexNames[n.typ], n.val)
+	}
+	custom, ok := t.customScalars[n.val]
+	if !ok {
+		t.errorf("Undeclared scalar %q: pass a *graphql.Scalar for it via the scalars argument to BuildSchemaConfig", n.val)
+	}
+	t.scalars[n.val] = custom
+}
+
+func (t *parseContext) processTypeNode(schemaConfig *graphql.SchemaConfig, desc string) {
 	n := t.next()
 	if n.typ != itemIdentifier {
 		t.errorf("No identifier after type keyword, got t: %#v, v: %#v", LexNames[n.typ], n.val)
 	}
 	x := t.next()
+	var interfaces []*graphql.Interface
+	if x.typ == itemImplements {
+		interfaces = t.parseImplements()
+		x = t.next()
+	}
 	if x.typ != itemBlockStart {
 		t.errorf("No block starting after Query, got t: %#v, v: %#v", LexNames[x.typ], x.val)
 	}
+	fields, fieldDirectives := t.parseFields()
+	for label, field := range fields {
+		if fn, ok := funcs[label]; ok {
+			field.Resolve = fn
+		}
+	}
+	t.applyFieldDirectives(n.val, fields, fieldDirectives)
+	if n.val == "Query" {
+		schemaConfig.Query = graphql.NewObject(
+			graphql.ObjectConfig{
+				Name:        "RootQuery",
+				Fields:      fields,
+				Interfaces:  interfaces,
+				Description: desc,
+			},
+		)
+	} else if n.val == "Mutation" {
+		schemaConfig.Mutation = graphql.NewObject(
+			graphql.ObjectConfig{
+				Name:        "RootMutation",
+				Fields:      fields,
+				Interfaces:  interfaces,
+				Description: desc,
+			},
+		)
+	} else if n.val == "Subscription" {
+		schemaConfig.Subscription = graphql.NewObject(
+			graphql.ObjectConfig{
+				Name:        "RootSubscription",
+				Fields:      fields,
+				Interfaces:  interfaces,
+				Description: desc,
+			},
+		)
+	} else {
+		t.objects[n.val] = graphql.NewObject(
+			graphql.ObjectConfig{
+				Name:        n.val,
+				Fields:      fields,
+				Interfaces:  interfaces,
+				Description: desc,
+			},
+		)
+	}
+}
+
+// parseFields consumes a brace-delimited field list - the body shared by
+// `type`, and `interface` declarations - up to and including the closing
+// '}', and returns the graphql.Fields it describes, along with any
+// non-deprecation directive found on each field, keyed by field label.
+// It doesn't attach resolvers; processTypeNode does that afterward, since
+// interface fields are never resolved directly.
+func (t *parseContext) parseFields() (graphql.Fields, map[string][]Directive) {
 	fields := graphql.Fields{}
-Loop:
+	fieldDirectives := map[string][]Directive{}
 	for {
-		isArray := false
 		var params graphql.FieldConfigArgument
-		x = t.next()
+		x := t.next()
+		desc := ""
+		if x.typ == itemStringValue {
+			desc = x.val
+			x = t.next()
+		}
 		if x.typ == itemBlockEnd {
-			break Loop
+			return fields, fieldDirectives
 		}
 		if x.typ != itemIdentifier {
 			t.errorf("No label after block start, got t: %#v, v: %#v", LexNames[x.typ], x.val)
@@ -151,75 +361,235 @@ Loop:
 		if x.typ != itemColon {
 			t.errorf("No colon or ( after label, t: %#v, v: %#v", LexNames[x.typ], x.val)
 		}
-		x = t.next()
-		if x.typ == itemLeftBracket {
-			isArray = true
-			x = t.next()
-		}
-		tname := x.val
-		if x.typ != itemIdentifier {
-			t.errorf("No type identifier after label, t: %#v, v: %#v", LexNames[x.typ], x.val)
+		field := &graphql.Field{Type: t.parseTypeRef(), Description: desc}
+		if params != nil {
+			field.Args = params
 		}
-		if isArray {
-			x = t.next()
-			if x.typ != itemRightBracket {
-				t.errorf("No closing ] after identifier, t: %#v, v: %#v", LexNames[x.typ], x.val)
-			}
+		if rest := t.applyDeprecation(field, nil); rest != nil {
+			fieldDirectives[label] = rest
 		}
-		var vtype graphql.Output
+		fields[label] = field
+	}
+}
 
-		fields[label] = &graphql.Field{}
+// applyDeprecation consumes any `@directive(...)` annotations following a
+// field or enum value. If one of them is @deprecated it sets the reason on
+// whichever of field and value is non-nil (field.DeprecationReason or
+// value.DeprecationReason) and doesn't return it; every other directive is
+// returned for the caller to collect and hand to a registered handler.
+func (t *parseContext) applyDeprecation(field *graphql.Field, value *graphql.EnumValueConfig) []Directive {
+	var rest []Directive
+	for _, d := range t.parseDirectives() {
+		if d.Name != "deprecated" {
+			rest = append(rest, d)
+			continue
+		}
+		reason := "No longer supported"
+		if r, ok := d.Args["reason"].(string); ok {
+			reason = r
+		}
+		if field != nil {
+			field.DeprecationReason = reason
+		}
+		if value != nil {
+			value.DeprecationReason = reason
+		}
+	}
+	return rest
+}
 
-		if _, ok := t.scalars[tname]; !ok {
-			if _, ok := t.objects[tname]; !ok {
-				if _, ok := t.unions[tname]; !ok {
-					t.errorf("Not declared scalar,object type or union (yet) '%s'", x.val)
-				} else {
-					vtype = t.unions[tname]
-				}
-			} else {
-				vtype = t.objects[tname]
-			}
+// parseDirectives consumes zero or more `@name(arg: literal, ...)`
+// directives following a field or enum value declaration.
+func (t *parseContext) parseDirectives() []Directive {
+	var directives []Directive
+	for {
+		x := t.next()
+		if x.typ != itemAt {
+			t.backup()
+			return directives
+		}
+		n := t.next()
+		if n.typ != itemIdentifier {
+			t.errorf("No directive name after '@', got t: %#v, v: %#v", LexNames[n.typ], n.val)
+		}
+		d := Directive{Name: n.val}
+		if x = t.next(); x.typ == itemLeftParen {
+			d.Args = t.parseDirectiveArgs()
 		} else {
-			vtype = t.scalars[tname]
+			t.backup()
 		}
+		directives = append(directives, d)
+	}
+}
 
-		if isArray {
-			vtype = graphql.NewList(vtype)
+// parseDirectiveArgs consumes a directive's `(name: literal, ...)` argument
+// list, up to and including the closing ')'.
+func (t *parseContext) parseDirectiveArgs() map[string]interface{} {
+	args := map[string]interface{}{}
+	for {
+		x := t.next()
+		if x.typ == itemRightParen {
+			return args
+		}
+		if x.typ != itemIdentifier {
+			t.errorf("No label in directive argument, got t: %#v, v: %#v", LexNames[x.typ], x.val)
+		}
+		label := x.val
+		if c := t.next(); c.typ != itemColon {
+			t.errorf("No colon after %s in directive argument, got t: %#v, v: %#v", label, LexNames[c.typ], c.val)
+		}
+		args[label] = t.parseLiteralValue()
+		if x = t.next(); x.typ != itemComma {
+			t.backup()
 		}
+	}
+}
 
-		fields[label].Type = vtype
+// parseImplements consumes the `Name (& Name)*` list following an
+// `implements` keyword, stopping (via backup) at the first token that
+// isn't an '&'.
+func (t *parseContext) parseImplements() []*graphql.Interface {
+	var interfaces []*graphql.Interface
+	for {
+		x := t.next()
+		if x.typ != itemIdentifier {
+			t.errorf("No interface name after implements, got t: %#v, v: %#v", LexNames[x.typ], x.val)
+		}
+		iface, ok := t.interfaces[x.val]
+		if !ok {
+			t.errorf("Not declared interface (yet) '%s'", x.val)
+		}
+		interfaces = append(interfaces, iface)
+		if x = t.next(); x.typ != itemAmpersand {
+			t.backup()
+			return interfaces
+		}
+	}
+}
 
-		if params != nil {
-			fields[label].Args = params
-			params = nil
+// processEnumNode parses an `enum Name { VALUE1 VALUE2 ... }` declaration.
+func (t *parseContext) processEnumNode(desc string) {
+	n := t.next()
+	if n.typ != itemIdentifier {
+		t.errorf("No identifier after enum keyword, got t: %#v, v: %#v", LexNames[n.typ], n.val)
+	}
+	x := t.next()
+	if x.typ != itemBlockStart {
+		t.errorf("No block starting after enum name, got t: %#v, v: %#v", LexNames[x.typ], x.val)
+	}
+	values := graphql.EnumValueConfigMap{}
+	for {
+		x = t.next()
+		valueDesc := ""
+		if x.typ == itemStringValue {
+			valueDesc = x.val
+			x = t.next()
+		}
+		if x.typ == itemBlockEnd {
+			break
+		}
+		if x.typ != itemIdentifier {
+			t.errorf("No value after block start, got t: %#v, v: %#v", LexNames[x.typ], x.val)
+		}
+		value := &graphql.EnumValueConfig{Value: x.val, Description: valueDesc}
+		if rest := t.applyDeprecation(nil, value); rest != nil {
+			t.directives[n.val+"."+x.val] = rest
 		}
+		values[x.val] = value
+	}
+	t.enums[n.val] = graphql.NewEnum(graphql.EnumConfig{
+		Name:        n.val,
+		Values:      values,
+		Description: desc,
+	})
+}
 
-		if _, ok := funcs[label]; ok {
-			fields[label].Resolve = funcs[label]
+// processInterfaceNode parses an `interface Name { ... }` declaration. Its
+// ResolveType callback, required by graphql-go to pick the concrete object
+// type for a value at execution time, comes from the resolveTypes map
+// passed to BuildSchemaConfig, keyed by this interface's name.
+func (t *parseContext) processInterfaceNode(desc string) {
+	n := t.next()
+	if n.typ != itemIdentifier {
+		t.errorf("No identifier after interface keyword, got t: %#v, v: %#v", LexNames[n.typ], n.val)
+	}
+	x := t.next()
+	if x.typ != itemBlockStart {
+		t.errorf("No block starting after interface name, got t: %#v, v: %#v", LexNames[x.typ], x.val)
+	}
+	fields, fieldDirectives := t.parseFields()
+	t.applyFieldDirectives(n.val, fields, fieldDirectives)
+	t.interfaces[n.val] = graphql.NewInterface(graphql.InterfaceConfig{
+		Name:        n.val,
+		Fields:      fields,
+		ResolveType: t.resolveTypes[n.val],
+		Description: desc,
+	})
+}
+
+// applyFieldDirectives qualifies each field's collected non-deprecation
+// directives with "typeName.fieldName" into t.directives, and hands the
+// field to any handler registered for that directive's name.
+func (t *parseContext) applyFieldDirectives(typeName string, fields graphql.Fields, fieldDirectives map[string][]Directive) {
+	for label, ds := range fieldDirectives {
+		t.directives[typeName+"."+label] = ds
+		for _, d := range ds {
+			if handler, ok := t.directiveHandlers[d.Name]; ok {
+				handler(d.Name, d.Args, fields[label])
+			}
 		}
 	}
-	if n.val == "Query" {
-		schemaConfig.Query = graphql.NewObject(
-			graphql.ObjectConfig{
-				Name:   "RootQuery",
-				Fields: fields,
-			},
-		)
-	} else {
-		t.objects[n.val] = graphql.NewObject(
-			graphql.ObjectConfig{
-				Name:   n.val,
-				Fields: fields,
-			},
-		)
+}
+
+// processInputNode parses an `input Name { ... }` declaration. The
+// resulting *graphql.InputObject can be used as an argument type, the same
+// way a scalar, object or union is used.
+func (t *parseContext) processInputNode(desc string) {
+	n := t.next()
+	if n.typ != itemIdentifier {
+		t.errorf("No identifier after input keyword, got t: %#v, v: %#v", LexNames[n.typ], n.val)
+	}
+	x := t.next()
+	if x.typ != itemBlockStart {
+		t.errorf("No block starting after input name, got t: %#v, v: %#v", LexNames[x.typ], x.val)
+	}
+	fields := graphql.InputObjectConfigFieldMap{}
+	for {
+		x = t.next()
+		fieldDesc := ""
+		if x.typ == itemStringValue {
+			fieldDesc = x.val
+			x = t.next()
+		}
+		if x.typ == itemBlockEnd {
+			break
+		}
+		if x.typ != itemIdentifier {
+			t.errorf("No label after block start, got t: %#v, v: %#v", LexNames[x.typ], x.val)
+		}
+		label := x.val
+		x = t.next()
+		if x.typ != itemColon {
+			t.errorf("No colon after label, got t: %#v, v: %#v", LexNames[x.typ], x.val)
+		}
+		fields[label] = &graphql.InputObjectFieldConfig{Type: t.parseTypeRef(), Description: fieldDesc}
 	}
+	t.inputs[n.val] = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        n.val,
+		Fields:      fields,
+		Description: desc,
+	})
 }
 
 func (t *parseContext) handleParams() graphql.FieldConfigArgument {
 	args := graphql.FieldConfigArgument{}
 	for {
 		x := t.next()
+		desc := ""
+		if x.typ == itemStringValue {
+			desc = x.val
+			x = t.next()
+		}
 		if x.typ != itemIdentifier {
 			t.errorf("No label in argument, got t: %#v, v: %#v", LexNames[x.typ], x.val)
 		}
@@ -228,25 +598,16 @@ func (t *parseContext) handleParams() graphql.FieldConfigArgument {
 		if x.typ != itemColon {
 			t.errorf("No colon after label, got t: %#v, v: %#v", LexNames[x.typ], x.val)
 		}
-		x = t.next()
-		var vtype graphql.Output
-		if _, ok := t.scalars[x.val]; !ok {
-			if _, ok := t.objects[x.val]; !ok {
-				if _, ok := t.unions[x.val]; !ok {
-					t.errorf("Not declared scalar,object type or union (yet) '%s'", x.val)
-				} else {
-					vtype = t.unions[x.val]
-				}
-			} else {
-				vtype = t.objects[x.val]
-			}
-		} else {
-			vtype = t.scalars[x.val]
-		}
-		args[label] = &graphql.ArgumentConfig{
-			Type: vtype,
+		arg := &graphql.ArgumentConfig{
+			Type:        t.parseTypeRef(),
+			Description: desc,
 		}
 		x = t.next()
+		if x.typ == itemEqual {
+			arg.DefaultValue = t.parseLiteralValue()
+			x = t.next()
+		}
+		args[label] = arg
 		if x.typ == itemRightParen {
 			return args
 		}
@@ -256,6 +617,117 @@ func (t *parseContext) handleParams() graphql.FieldConfigArgument {
 	}
 }
 
+// parseLiteralValue parses a single SDL literal - a string, int, float,
+// bool, bare enum value identifier, bracketed list, or braced object - into
+// the plain Go value graphql-go expects for an ArgumentConfig.DefaultValue.
+func (t *parseContext) parseLiteralValue() interface{} {
+	x := t.next()
+	switch x.typ {
+	case itemStringValue:
+		return x.val
+	case itemLeftBracket:
+		var list []interface{}
+		for {
+			if x = t.next(); x.typ == itemRightBracket {
+				break
+			}
+			t.backup()
+			list = append(list, t.parseLiteralValue())
+			if x = t.next(); x.typ != itemComma {
+				t.backup()
+			}
+		}
+		return list
+	case itemBlockStart:
+		obj := map[string]interface{}{}
+		for {
+			n := t.next()
+			if n.typ == itemBlockEnd {
+				break
+			}
+			if n.typ != itemIdentifier {
+				t.errorf("No label in default object value, got t: %#v, v: %#v", LexNames[n.typ], n.val)
+			}
+			if c := t.next(); c.typ != itemColon {
+				t.errorf("No colon after %s in default object value, got t: %#v, v: %#v", n.val, LexNames[c.typ], c.val)
+			}
+			obj[n.val] = t.parseLiteralValue()
+		}
+		return obj
+	case itemIdentifier:
+		switch x.val {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+		if i, err := strconv.ParseInt(x.val, 10, 64); err == nil {
+			return int(i)
+		}
+		if f, err := strconv.ParseFloat(x.val, 64); err == nil {
+			return f
+		}
+		return x.val // bare enum value
+	default:
+		t.errorf("No value after '=', got t: %#v, v: %#v", LexNames[x.typ], x.val)
+		return nil
+	}
+}
+
+// parseTypeRef consumes a type reference - a named type, a list of a type
+// reference in [...], or either of those followed by '!' - and returns the
+// graphql.Output it describes. It recurses on itself to handle arbitrary
+// nesting, so "[[Int!]!]!" parses the same way graphql-go would build it
+// by hand.
+func (t *parseContext) parseTypeRef() graphql.Output {
+	x := t.next()
+	var vtype graphql.Output
+	switch x.typ {
+	case itemLeftBracket:
+		inner := t.parseTypeRef()
+		x = t.next()
+		if x.typ != itemRightBracket {
+			t.errorf("No closing ] after list type, t: %#v, v: %#v", LexNames[x.typ], x.val)
+		}
+		vtype = graphql.NewList(inner)
+	case itemIdentifier:
+		vtype = t.resolveNamedType(x.val)
+	default:
+		t.errorf("No type identifier after label, t: %#v, v: %#v", LexNames[x.typ], x.val)
+	}
+	if x = t.next(); x.typ == itemExclamation {
+		vtype = graphql.NewNonNull(vtype)
+	} else {
+		t.backup()
+	}
+	return vtype
+}
+
+// resolveNamedType looks up a declared scalar, object, union, enum,
+// interface or input type by its SDL name.
+func (t *parseContext) resolveNamedType(tname string) graphql.Output {
+	if _, ok := t.scalars[tname]; ok {
+		return t.scalars[tname]
+	}
+	if _, ok := t.objects[tname]; ok {
+		return t.objects[tname]
+	}
+	if _, ok := t.unions[tname]; ok {
+		return t.unions[tname]
+	}
+	if _, ok := t.enums[tname]; ok {
+		return t.enums[tname]
+	}
+	if _, ok := t.interfaces[tname]; ok {
+		return t.interfaces[tname]
+	}
+	if _, ok := t.inputs[tname]; ok {
+		return t.inputs[tname]
+	}
+	t.errorf("Not declared scalar, object, union, enum, interface or input type (yet) '%s'", tname)
+	return nil
+}
+
 // IsEmptyTree reports whether this parseContext (node) is empty of everything but space.
 func (t *parseContext) isEmpty() bool {
 	if t.Root == nil {
@@ -277,7 +749,7 @@ func (t *parseContext) error(err error) {
 }
 
 // expect consumes the next token and guarantees it has the required type.
-func (t *parseContext) expect(expected token, context string) item {
+func (t *parseContext) expect(expected Token, context string) item {
 	token := t.nextNonSpace()
 	if token.typ != expected {
 		t.unexpected(token, context)
@@ -286,7 +758,7 @@ func (t *parseContext) expect(expected token, context string) item {
 }
 
 // expectOneOf consumes the next token and guarantees it has one of the required types.
-func (t *parseContext) expectOneOf(expectedTokens []token, context string) item {
+func (t *parseContext) expectOneOf(expectedTokens []Token, context string) item {
 	token := t.nextNonSpace()
 	found := false
 	var foundItem item
@@ -46,6 +46,65 @@ var parseTests = []parseTest{
 					},
 				},
 			}})}},
+	{"required field", "type Query {\n hello: String \n name: String! \n}\n", noError,
+		graphql.SchemaConfig{Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "RootQuery",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "world", nil
+					},
+				},
+				"name": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}})}},
+	{"required list of required elements", "type Query {\n hello: String \n tags: [String!]! \n}\n", noError,
+		graphql.SchemaConfig{Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "RootQuery",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "world", nil
+					},
+				},
+				"tags": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				},
+			}})}},
+}
+
+// sameShape reports whether got and want declare the same root operation
+// types: same field names, types and descriptions. It deliberately ignores
+// Resolve - two schemas built from independently-declared closures can never
+// be reflect.DeepEqual on that field, and closure identity isn't part of the
+// shape parseTests are checking.
+func sameShape(got, want graphql.SchemaConfig) bool {
+	return sameObject(got.Query, want.Query) &&
+		sameObject(got.Mutation, want.Mutation) &&
+		sameObject(got.Subscription, want.Subscription)
+}
+
+func sameObject(got, want *graphql.Object) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	if got.Name() != want.Name() || got.Description() != want.Description() {
+		return false
+	}
+	gotFields, wantFields := got.Fields(), want.Fields()
+	if len(gotFields) != len(wantFields) {
+		return false
+	}
+	for label, g := range gotFields {
+		w, ok := wantFields[label]
+		if !ok || g.Description != w.Description || g.Type.String() != w.Type.String() || len(g.Args) != len(w.Args) {
+			return false
+		}
+	}
+	return true
 }
 
 func TestBuildSchema(t *testing.T) {
@@ -55,7 +114,7 @@ func TestBuildSchema(t *testing.T) {
 				"hello": func(p graphql.ResolveParams) (interface{}, error) {
 					return "world", nil
 				},
-			})
+			}, nil, nil, nil)
 		switch {
 		case err == nil && !test.ok:
 			t.Errorf("%q: expected error; got none", test.name)
@@ -71,8 +130,8 @@ func TestBuildSchema(t *testing.T) {
 			continue
 		}
 
-		if !reflect.DeepEqual(schemaConfig, test.result) {
-			t.Errorf("%s expected %#v\n got\n%#v", test.name, test.result, schemaConfig)
+		if !sameShape(schemaConfig.SchemaConfig, test.result) {
+			t.Errorf("%s expected %#v\n got\n%#v", test.name, test.result, schemaConfig.SchemaConfig)
 		}
 	}
 }
@@ -92,7 +151,7 @@ func TestGraphql(t *testing.T) {
 				"hello": func(p graphql.ResolveParams) (interface{}, error) {
 					return "world", nil
 				},
-			}),
+			}, nil, nil, nil),
 			Expected: &graphql.Result{
 				Data: map[string]interface{}{
 					"hello": "world",
@@ -112,11 +171,145 @@ func TestGraphql(t *testing.T) {
 				"hex": func(p graphql.ResolveParams) (interface{}, error) {
 					return "#000000", nil
 				},
-			}),
+			}, nil, nil, nil),
 			Expected: &graphql.Result{
 				Data: map[string]interface{}{"hex": "#000000"},
 			},
 		},
+		{
+			Name: "required arg and list of required elements",
+			Query: `
+				query { shout(words: ["hi", "there"]) }
+			`,
+			Schema: MustBuildSchema(`
+			type Query {
+				shout(words: [String!]!): String!
+			}
+			`, map[string]graphql.FieldResolveFn{
+				"shout": func(p graphql.ResolveParams) (interface{}, error) {
+					words := p.Args["words"].([]interface{})
+					out := ""
+					for _, w := range words {
+						out += w.(string) + "! "
+					}
+					return out, nil
+				},
+			}, nil, nil, nil),
+			Expected: &graphql.Result{
+				Data: map[string]interface{}{"shout": "hi! there! "},
+			},
+		},
+		{
+			Name: "mutation",
+			Query: `
+				mutation { setName(name: "Ada") }
+			`,
+			Schema: MustBuildSchema(`
+			type Query {
+				hello: String
+			}
+			type Mutation {
+				setName(name: String!): String
+			}
+			`, map[string]graphql.FieldResolveFn{
+				"hello": func(p graphql.ResolveParams) (interface{}, error) {
+					return "world", nil
+				},
+				"setName": func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Args["name"], nil
+				},
+			}, nil, nil, nil),
+			Expected: &graphql.Result{
+				Data: map[string]interface{}{"setName": "Ada"},
+			},
+		},
+		{
+			Name: "enum, interface and input",
+			Query: `
+				query { search(filter: {status: ACTIVE}) { name ... on User { status } } }
+			`,
+			Schema: MustBuildSchema(`
+			enum Status {
+				ACTIVE
+				RETIRED
+			}
+			interface Named {
+				name: String
+			}
+			type User implements Named {
+				name: String
+				status: Status
+			}
+			input SearchFilter {
+				status: Status
+			}
+			type Query {
+				search(filter: SearchFilter): Named
+			}
+			`, map[string]graphql.FieldResolveFn{
+				"search": func(p graphql.ResolveParams) (interface{}, error) {
+					filter := p.Args["filter"].(map[string]interface{})
+					return map[string]interface{}{
+						"name":   "Ada",
+						"status": filter["status"],
+					}, nil
+				},
+			}, map[string]graphql.ResolveTypeFn{
+				"Named": func(p graphql.ResolveTypeParams) *graphql.Object {
+					obj, _ := p.Info.Schema.Type("User").(*graphql.Object)
+					return obj
+				},
+			}, nil, nil),
+			Expected: &graphql.Result{
+				Data: map[string]interface{}{
+					"search": map[string]interface{}{"name": "Ada", "status": "ACTIVE"},
+				},
+			},
+		},
+		{
+			Name: "argument default value",
+			Query: `
+				query { greet }
+			`,
+			Schema: MustBuildSchema(`
+			type Query {
+				greet(name: String = "World"): String
+			}
+			`, map[string]graphql.FieldResolveFn{
+				"greet": func(p graphql.ResolveParams) (interface{}, error) {
+					return "Hello, " + p.Args["name"].(string), nil
+				},
+			}, nil, nil, nil),
+			Expected: &graphql.Result{
+				Data: map[string]interface{}{"greet": "Hello, World"},
+			},
+		},
+		{
+			Name: "custom scalar",
+			Query: `
+				query { id }
+			`,
+			Schema: MustBuildSchema(`
+			scalar UUID
+			type Query {
+				id: UUID
+			}
+			`, map[string]graphql.FieldResolveFn{
+				"id": func(p graphql.ResolveParams) (interface{}, error) {
+					return "123e4567-e89b-12d3-a456-426614174000", nil
+				},
+			}, nil, map[string]*graphql.Scalar{
+				"UUID": graphql.NewScalar(graphql.ScalarConfig{
+					Name: "UUID",
+					Serialize: func(value interface{}) interface{} {
+						return value
+					},
+				}),
+			}, nil),
+			Expected: &graphql.Result{
+				Data: map[string]interface{}{"id": "123e4567-e89b-12d3-a456-426614174000"},
+			},
+		},
 	}
 	for _, test := range Tests {
 		params := graphql.Params{
@@ -133,3 +326,55 @@ func TestGraphql(t *testing.T) {
 	}
 
 }
+
+func TestDescriptionsSurfaceThroughIntrospection(t *testing.T) {
+	schema := MustBuildSchema(`
+	"A person who can be searched for"
+	type User {
+		"The person's full name"
+		name: String
+	}
+	type Query {
+		"Look up a user by name"
+		user(
+			"The name to search for"
+			name: String!
+		): User
+	}
+	`, map[string]graphql.FieldResolveFn{
+		"user": func(p graphql.ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"name": p.Args["name"]}, nil
+		},
+	}, nil, nil, nil)
+
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `
+			{
+				__type(name: "User") {
+					name
+					description
+					fields { name description }
+				}
+			}
+		`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
+	}
+
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"__type": map[string]interface{}{
+				"name":        "User",
+				"description": "A person who can be searched for",
+				"fields": []interface{}{
+					map[string]interface{}{"name": "name", "description": "The person's full name"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("wrong result, graphql result diff: %v", testutil.Diff(expected, result))
+	}
+}